@@ -14,4 +14,6 @@ const (
 const (
 	ConfigFileFlag = "config-file"
 	ForceFlag      = "force"
+	DryRunFlag     = "dry-run"
+	PruneFlag      = "prune"
 )
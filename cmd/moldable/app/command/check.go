@@ -0,0 +1,30 @@
+package command
+
+import (
+	"github.com/nuvrel/moldable/cmd/moldable/app"
+	"github.com/nuvrel/moldable/internal/command"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+func NewCheck(r command.Runnable) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "check",
+		Short:        "Fails when generated files are out of date",
+		Long:         `Renders every generated file in memory, prints a unified diff for each one that is stale or missing and exits with a non-zero status, without touching the working tree.`,
+		Args:         cobra.NoArgs,
+		RunE:         r,
+		SilenceUsage: true,
+	}
+
+	{
+		fs := new(pflag.FlagSet)
+
+		fs.StringP(app.ConfigFileFlag, "c", app.ConfigFile, "path to config file")
+		fs.Bool(app.PruneFlag, false, "remove generated files no longer produced by the config")
+
+		cmd.Flags().AddFlagSet(fs)
+	}
+
+	return cmd
+}
@@ -23,6 +23,8 @@ func NewRoot(r command.Runnable) *cobra.Command {
 		fs := new(pflag.FlagSet)
 
 		fs.StringP(app.ConfigFileFlag, "c", app.ConfigFile, "path to config file")
+		fs.Bool(app.PruneFlag, false, "remove generated files no longer produced by the config")
+		fs.Bool(app.DryRunFlag, false, "print generated files to stdout instead of writing them")
 
 		cmd.Flags().AddFlagSet(fs)
 	}
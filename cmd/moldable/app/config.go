@@ -4,18 +4,35 @@ import (
 	"errors"
 	"fmt"
 	"go/token"
+	"path/filepath"
+	"slices"
 	"strings"
-	"unicode"
+
+	"github.com/nuvrel/moldable/internal/namefilter"
+	"github.com/nuvrel/moldable/internal/structcollector"
+)
+
+const (
+	UnreachableSkipMethod = "skip-method"
+	UnreachableSkipStruct = "skip-struct"
+	UnreachableFail       = "fail"
 )
 
 type Config struct {
-	Output   Output    `koanf:"output"`
-	Packages []Package `koanf:"packages"`
+	Output      Output    `koanf:"output"`
+	Unreachable string    `koanf:"unreachable"`
+	Prune       bool      `koanf:"prune"`
+	Scan        []string  `koanf:"scan"`
+	Compose     bool      `koanf:"compose"`
+	Deep        bool      `koanf:"deep"`
+	Packages    []Package `koanf:"packages"`
 }
 
 func (c Config) Check() error {
-	if err := c.Output.check(); err != nil {
-		return fmt.Errorf("checking output: %w", err)
+	switch c.Unreachable {
+	case "", UnreachableSkipMethod, UnreachableSkipStruct, UnreachableFail:
+	default:
+		return fmt.Errorf("unreachable must be one of %q, %q or %q", UnreachableSkipMethod, UnreachableSkipStruct, UnreachableFail)
 	}
 
 	if len(c.Packages) == 0 {
@@ -23,6 +40,7 @@ func (c Config) Check() error {
 	}
 
 	seen := make(map[string]bool)
+	dirs := make(map[string]string)
 
 	for _, p := range c.Packages {
 		if _, ok := seen[p.Path]; ok {
@@ -33,12 +51,59 @@ func (c Config) Check() error {
 			return fmt.Errorf("checking package %q: %w", p.Path, err)
 		}
 
+		out := c.OutputFor(p)
+
+		if err := out.check(); err != nil {
+			return fmt.Errorf("checking output of package %q: %w", p.Path, err)
+		}
+
+		dir := filepath.Clean(out.Dir)
+
+		if name, ok := dirs[dir]; ok && name != out.Package {
+			return fmt.Errorf("output directory %q is used with package names %q and %q", out.Dir, name, out.Package)
+		}
+
+		dirs[dir] = out.Package
 		seen[p.Path] = true
 	}
 
 	return nil
 }
 
+// OutputFor returns the output settings of p, using the global output block
+// for anything the package does not override.
+func (c Config) OutputFor(p Package) Output {
+	return c.Output.merge(p.Output)
+}
+
+// ScanPatterns returns the package patterns searched for method calls by
+// packages with only_used set, the whole module by default.
+func (c Config) ScanPatterns() []string {
+	if len(c.Scan) == 0 {
+		return []string{"./..."}
+	}
+
+	return c.Scan
+}
+
+// NeedsScan reports whether any package narrows its interfaces to the
+// methods that are actually called, which requires scanning for calls.
+func (c Config) NeedsScan() bool {
+	return slices.ContainsFunc(c.Packages, func(p Package) bool {
+		return p.OnlyUsed
+	})
+}
+
+// UnreachablePolicy returns what to do with methods whose signatures mention
+// types the output package cannot refer to, skipping the method by default.
+func (c Config) UnreachablePolicy() string {
+	if c.Unreachable == "" {
+		return UnreachableSkipMethod
+	}
+
+	return c.Unreachable
+}
+
 func (c Config) Paths() []string {
 	paths := make([]string, 0, len(c.Packages))
 
@@ -49,11 +114,66 @@ func (c Config) Paths() []string {
 	return paths
 }
 
+const (
+	EmitAssertions    = "assertions"
+	EmitMoq           = "moq"
+	EmitGomock        = "gomock"
+	EmitTestify       = "testify"
+	EmitDelegate      = "delegate"
+	EmitInstrument    = "instrument"
+	EmitNoop          = "noop"
+	EmitUnimplemented = "unimplemented"
+)
+
+var emitters = []string{
+	EmitAssertions,
+	EmitMoq,
+	EmitGomock,
+	EmitTestify,
+	EmitDelegate,
+	EmitInstrument,
+	EmitNoop,
+	EmitUnimplemented,
+}
+
 type Output struct {
-	Dir      string `koanf:"dir"`
-	Package  string `koanf:"package"`
-	Filename string `koanf:"filename"`
-	Naming   Naming `koanf:"naming"`
+	Dir      string   `koanf:"dir"`
+	Package  string   `koanf:"package"`
+	Filename string   `koanf:"filename"`
+	Naming   Naming   `koanf:"naming"`
+	Emit     []string `koanf:"emit"`
+	Embed    []string `koanf:"embed"`
+}
+
+// Emits reports whether the given extra declaration kind is enabled.
+func (o Output) Emits(kind string) bool {
+	return slices.Contains(o.Emit, kind)
+}
+
+func (o Output) merge(override Output) Output {
+	if override.Dir != "" {
+		o.Dir = override.Dir
+	}
+
+	if override.Package != "" {
+		o.Package = override.Package
+	}
+
+	if override.Filename != "" {
+		o.Filename = override.Filename
+	}
+
+	o.Naming = o.Naming.merge(override.Naming)
+
+	if override.Emit != nil {
+		o.Emit = override.Emit
+	}
+
+	if override.Embed != nil {
+		o.Embed = override.Embed
+	}
+
+	return o
 }
 
 func (o Output) check() error {
@@ -81,29 +201,63 @@ func (o Output) check() error {
 		return fmt.Errorf("checking naming: %w", err)
 	}
 
+	for _, kind := range o.Emit {
+		if !slices.Contains(emitters, kind) {
+			return fmt.Errorf("unknown emit kind %q, expected one of %v", kind, emitters)
+		}
+	}
+
+	for _, ref := range o.Embed {
+		if err := checkEmbed(ref); err != nil {
+			return fmt.Errorf("checking embed %q: %w", ref, err)
+		}
+	}
+
 	return nil
 }
 
-type Naming struct {
-	Suffix string `koanf:"suffix"`
+// EmbedStandard in the embed list stands for a set of well-known standard
+// library interfaces, such as io.ReadCloser or fmt.Stringer.
+const EmbedStandard = "std"
+
+// EmbedRef splits an embed entry into the import path and the name of the
+// interface it refers to. The path is empty for interfaces of the source
+// package, written as a bare name.
+func EmbedRef(ref string) (string, string) {
+	i := strings.LastIndex(ref, ".")
+	if i < 0 {
+		return "", ref
+	}
+
+	return ref[:i], ref[i+1:]
 }
 
-func (n Naming) check() error {
-	if strings.TrimSpace(n.Suffix) == "" {
-		return errors.New("suffix is required")
+func checkEmbed(ref string) error {
+	if ref == EmbedStandard {
+		return nil
 	}
 
-	for i, r := range n.Suffix {
-		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' {
-			return fmt.Errorf("suffix contains invalid character '%c' at position %d", r, i)
-		}
+	path, name := EmbedRef(ref)
+
+	if !token.IsIdentifier(name) || !token.IsExported(name) {
+		return errors.New("must name an exported interface")
+	}
+
+	if strings.Contains(ref, ".") && path == "" {
+		return errors.New("import path is empty")
 	}
 
 	return nil
 }
 
 type Package struct {
-	Path string `koanf:"path"`
+	Path     string   `koanf:"path"`
+	Output   Output   `koanf:"output"`
+	Structs  Filter   `koanf:"structs"`
+	Methods  Filter   `koanf:"methods"`
+	OnlyUsed bool     `koanf:"only_used"`
+	Kinds    []string `koanf:"kinds"`
+	Facade   string   `koanf:"facade"`
 }
 
 func (p Package) check() error {
@@ -111,5 +265,32 @@ func (p Package) check() error {
 		return errors.New("package path is required")
 	}
 
+	if _, err := p.Structs.Compile(); err != nil {
+		return fmt.Errorf("checking structs filter: %w", err)
+	}
+
+	if _, err := p.Methods.Compile(); err != nil {
+		return fmt.Errorf("checking methods filter: %w", err)
+	}
+
+	if p.Facade != "" && !token.IsIdentifier(p.Facade) {
+		return fmt.Errorf("facade name %q must be a valid identifier", p.Facade)
+	}
+
+	for _, kind := range p.Kinds {
+		if !slices.Contains(structcollector.Kinds, kind) {
+			return fmt.Errorf("unknown kind %q, expected one of %v", kind, structcollector.Kinds)
+		}
+	}
+
 	return nil
 }
+
+type Filter struct {
+	Include []string `koanf:"include"`
+	Exclude []string `koanf:"exclude"`
+}
+
+func (f Filter) Compile() (*namefilter.Filter, error) {
+	return namefilter.New(f.Include, f.Exclude)
+}
@@ -0,0 +1,154 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"go/token"
+	"maps"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const DefaultNamingTemplate = "{prefix}{Struct}{suffix}"
+
+var placeholderPattern = regexp.MustCompile(`\{[^{}]*\}`)
+
+type Naming struct {
+	Prefix   string            `koanf:"prefix"`
+	Suffix   string            `koanf:"suffix"`
+	Template string            `koanf:"template"`
+	Renames  map[string]string `koanf:"renames"`
+}
+
+// Name renders the interface name for a struct of the given package. Explicit
+// renames win over the template.
+func (n Naming) Name(structName, packageName string) string {
+	if name, ok := n.Renames[structName]; ok {
+		return name
+	}
+
+	tmpl := n.Template
+	if tmpl == "" {
+		tmpl = DefaultNamingTemplate
+	}
+
+	return strings.NewReplacer(
+		"{prefix}", n.Prefix,
+		"{suffix}", n.Suffix,
+		"{Struct}", structName,
+		"{struct}", lowerFirst(structName),
+		"{STRUCT}", strings.ToUpper(structName),
+		"{Package}", upperFirst(packageName),
+		"{package}", packageName,
+		"{PACKAGE}", strings.ToUpper(packageName),
+	).Replace(tmpl)
+}
+
+func (n Naming) merge(override Naming) Naming {
+	if override.Prefix != "" {
+		n.Prefix = override.Prefix
+	}
+
+	if override.Suffix != "" {
+		n.Suffix = override.Suffix
+	}
+
+	if override.Template != "" {
+		n.Template = override.Template
+	}
+
+	if len(override.Renames) > 0 {
+		renames := make(map[string]string, len(n.Renames)+len(override.Renames))
+
+		maps.Copy(renames, n.Renames)
+		maps.Copy(renames, override.Renames)
+
+		n.Renames = renames
+	}
+
+	return n
+}
+
+func (n Naming) check() error {
+	if n.Template == "" && strings.TrimSpace(n.Prefix) == "" && strings.TrimSpace(n.Suffix) == "" {
+		return errors.New("suffix is required")
+	}
+
+	if err := checkAffix("prefix", n.Prefix); err != nil {
+		return err
+	}
+
+	if err := checkAffix("suffix", n.Suffix); err != nil {
+		return err
+	}
+
+	if n.Template != "" {
+		if err := checkTemplate(n.Template); err != nil {
+			return fmt.Errorf("checking template: %w", err)
+		}
+	}
+
+	for from, to := range n.Renames {
+		if !token.IsIdentifier(to) {
+			return fmt.Errorf("rename of %q must be a valid identifier, got %q", from, to)
+		}
+	}
+
+	return nil
+}
+
+func checkAffix(field, value string) error {
+	for i, r := range value {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' {
+			return fmt.Errorf("%s contains invalid character '%c' at position %d", field, r, i)
+		}
+	}
+
+	return nil
+}
+
+func checkTemplate(tmpl string) error {
+	hasStruct := false
+
+	for _, p := range placeholderPattern.FindAllString(tmpl, -1) {
+		switch p {
+		case "{Struct}", "{struct}", "{STRUCT}":
+			hasStruct = true
+		case "{prefix}", "{suffix}", "{Package}", "{package}", "{PACKAGE}":
+		default:
+			return fmt.Errorf("unknown placeholder %s", p)
+		}
+	}
+
+	if !hasStruct {
+		return errors.New("template must contain a {Struct}, {struct} or {STRUCT} placeholder")
+	}
+
+	if sample := (Naming{Template: tmpl}).Name("Sample", "sample"); !token.IsIdentifier(sample) {
+		return fmt.Errorf("template renders invalid identifier %q", sample)
+	}
+
+	return nil
+}
+
+func upperFirst(s string) string {
+	if s == "" {
+		return s
+	}
+
+	r, size := utf8.DecodeRuneInString(s)
+
+	return string(unicode.ToUpper(r)) + s[size:]
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+
+	r, size := utf8.DecodeRuneInString(s)
+
+	return string(unicode.ToLower(r)) + s[size:]
+}
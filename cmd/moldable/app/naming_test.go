@@ -0,0 +1,187 @@
+package app_test
+
+import (
+	"testing"
+
+	"github.com/nuvrel/moldable/cmd/moldable/app"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNamingName(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name   string
+		naming app.Naming
+		want   string
+	}{
+		{
+			name:   "default template",
+			naming: app.Naming{Prefix: "I", Suffix: "Contract"},
+			want:   "IHTTPClientContract",
+		},
+		{
+			name:   "custom template",
+			naming: app.Naming{Prefix: "Fake", Suffix: "API", Template: "{suffix}{Struct}{prefix}"},
+			want:   "APIHTTPClientFake",
+		},
+		{
+			name:   "lower struct",
+			naming: app.Naming{Template: "{struct}Iface"},
+			want:   "hTTPClientIface",
+		},
+		{
+			name:   "upper struct",
+			naming: app.Naming{Template: "{STRUCT}_IFACE"},
+			want:   "HTTPCLIENT_IFACE",
+		},
+		{
+			name:   "package",
+			naming: app.Naming{Template: "{Package}{Struct}"},
+			want:   "StorageHTTPClient",
+		},
+		{
+			name:   "lower package",
+			naming: app.Naming{Template: "{package}{Struct}"},
+			want:   "storageHTTPClient",
+		},
+		{
+			name:   "upper package",
+			naming: app.Naming{Template: "{PACKAGE}_{Struct}"},
+			want:   "STORAGE_HTTPClient",
+		},
+		{
+			name:   "repeated placeholder",
+			naming: app.Naming{Template: "{Struct}{Struct}"},
+			want:   "HTTPClientHTTPClient",
+		},
+		{
+			name:   "rename wins over the template",
+			naming: app.Naming{Suffix: "Contract", Renames: map[string]string{"HTTPClient": "Doer"}},
+			want:   "Doer",
+		},
+		{
+			name:   "rename of another struct",
+			naming: app.Naming{Suffix: "Contract", Renames: map[string]string{"Client": "Doer"}},
+			want:   "HTTPClientContract",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, c.want, c.naming.Name("HTTPClient", "storage"))
+		})
+	}
+}
+
+func TestOutputForNaming(t *testing.T) {
+	t.Parallel()
+
+	base := app.Naming{
+		Prefix:   "I",
+		Suffix:   "Contract",
+		Template: "{prefix}{Struct}{suffix}",
+		Renames:  map[string]string{"A": "X", "B": "Y"},
+	}
+
+	cases := []struct {
+		name     string
+		override app.Naming
+		want     app.Naming
+	}{
+		{
+			name:     "empty override",
+			override: app.Naming{},
+			want:     base,
+		},
+		{
+			name:     "affixes and template",
+			override: app.Naming{Prefix: "P", Suffix: "S", Template: "{Struct}{suffix}"},
+			want: app.Naming{
+				Prefix:   "P",
+				Suffix:   "S",
+				Template: "{Struct}{suffix}",
+				Renames:  base.Renames,
+			},
+		},
+		{
+			name:     "renames are merged",
+			override: app.Naming{Renames: map[string]string{"B": "Z", "C": "W"}},
+			want: app.Naming{
+				Prefix:   "I",
+				Suffix:   "Contract",
+				Template: "{prefix}{Struct}{suffix}",
+				Renames:  map[string]string{"A": "X", "B": "Z", "C": "W"},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := app.Config{Output: app.Output{Naming: base}}
+			p := app.Package{Output: app.Output{Naming: c.override}}
+
+			assert.Equal(t, c.want, cfg.OutputFor(p).Naming)
+		})
+	}
+
+	assert.Equal(t, map[string]string{"A": "X", "B": "Y"}, base.Renames, "merging must not modify the global naming")
+}
+
+func TestConfigCheckNaming(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name   string
+		naming app.Naming
+		err    string
+	}{
+		{name: "suffix", naming: app.Naming{Suffix: "Contract"}},
+		{name: "prefix", naming: app.Naming{Prefix: "I"}},
+		{name: "no affix", naming: app.Naming{}, err: "suffix is required"},
+		{name: "invalid suffix", naming: app.Naming{Suffix: "Con-tract"}, err: "suffix contains invalid character '-' at position 3"},
+		{name: "invalid prefix", naming: app.Naming{Prefix: "I.", Suffix: "Contract"}, err: "prefix contains invalid character '.' at position 1"},
+		{name: "valid rename", naming: app.Naming{Suffix: "Contract", Renames: map[string]string{"Client": "Doer"}}},
+		{name: "invalid rename", naming: app.Naming{Suffix: "Contract", Renames: map[string]string{"Client": "do-er"}}, err: "rename of \"Client\" must be a valid identifier, got \"do-er\""},
+		{name: "default template", naming: app.Naming{Template: "{prefix}{Struct}{suffix}"}},
+		{name: "lower struct", naming: app.Naming{Template: "{struct}Impl"}},
+		{name: "upper placeholders", naming: app.Naming{Template: "{PACKAGE}_{STRUCT}"}},
+		{name: "package placeholders", naming: app.Naming{Template: "{Package}{package}{Struct}"}},
+		{name: "affixes without struct", naming: app.Naming{Template: "{prefix}{suffix}"}, err: "must contain a {Struct}"},
+		{name: "no placeholder", naming: app.Naming{Template: "Contract"}, err: "must contain a {Struct}"},
+		{name: "unknown placeholder", naming: app.Naming{Template: "{Struct}{Method}"}, err: "unknown placeholder {Method}"},
+		{name: "spaced placeholder", naming: app.Naming{Template: "{ Struct }"}, err: "unknown placeholder { Struct }"},
+		{name: "invalid character", naming: app.Naming{Template: "{Struct}-Contract"}, err: "renders invalid identifier \"Sample-Contract\""},
+		{name: "leading digit", naming: app.Naming{Template: "1{Struct}"}, err: "renders invalid identifier \"1Sample\""},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := app.Config{
+				Output: app.Output{
+					Dir:      "./generated",
+					Package:  "contract",
+					Filename: "{package}.go",
+					Naming:   c.naming,
+				},
+				Packages: []app.Package{{Path: "example.com/p"}},
+			}
+
+			err := cfg.Check()
+
+			if c.err == "" {
+				assert.NoError(t, err)
+
+				return
+			}
+
+			assert.ErrorContains(t, err, c.err)
+		})
+	}
+}
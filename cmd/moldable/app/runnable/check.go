@@ -0,0 +1,41 @@
+package runnable
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/log"
+	"github.com/nuvrel/moldable/internal/astfile"
+	"github.com/nuvrel/moldable/internal/command"
+	"github.com/nuvrel/moldable/internal/generator"
+	"github.com/nuvrel/moldable/internal/reporter"
+	"github.com/spf13/cobra"
+)
+
+func NewCheck(l *log.Logger) command.Runnable {
+	return func(cmd *cobra.Command, args []string) error {
+		cfg, err := loadConfig(cmd)
+		if err != nil {
+			return err
+		}
+
+		checker := astfile.NewChecker(cmd.OutOrStdout())
+
+		gen := generator.New(cfg, reporter.NewLog(l), checker)
+
+		if err := gen.Generate(); err != nil {
+			return fmt.Errorf("generating interfaces: %w", err)
+		}
+
+		if stale := checker.Stale(); len(stale) > 0 {
+			for _, path := range stale {
+				l.Error("generated file is out of date", "filepath", path)
+			}
+
+			return fmt.Errorf("%d generated file(s) out of date, run %s to update them", len(stale), cmd.Root().Name())
+		}
+
+		l.Info("generated files are up to date")
+
+		return nil
+	}
+}
@@ -0,0 +1,28 @@
+package runnable
+
+import (
+	"fmt"
+
+	"github.com/nuvrel/moldable/cmd/moldable/app"
+	"github.com/nuvrel/moldable/internal/config"
+	"github.com/spf13/cobra"
+)
+
+func loadConfig(cmd *cobra.Command) (app.Config, error) {
+	filepath, _ := cmd.Flags().GetString(app.ConfigFileFlag)
+
+	cfg, err := config.LoadYaml[app.Config](filepath)
+	if err != nil {
+		return app.Config{}, fmt.Errorf("loading config: %w", err)
+	}
+
+	if err := cfg.Check(); err != nil {
+		return app.Config{}, fmt.Errorf("checking config: %w", err)
+	}
+
+	if prune, _ := cmd.Flags().GetBool(app.PruneFlag); prune {
+		cfg.Prune = true
+	}
+
+	return cfg, nil
+}
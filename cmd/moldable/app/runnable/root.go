@@ -6,8 +6,8 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/log"
 	"github.com/nuvrel/moldable/cmd/moldable/app"
+	"github.com/nuvrel/moldable/internal/astfile"
 	"github.com/nuvrel/moldable/internal/command"
-	"github.com/nuvrel/moldable/internal/config"
 	"github.com/nuvrel/moldable/internal/generator"
 	"github.com/nuvrel/moldable/internal/reporter"
 	"github.com/spf13/cobra"
@@ -15,23 +15,31 @@ import (
 
 func NewRoot(l *log.Logger) command.Runnable {
 	return func(cmd *cobra.Command, args []string) error {
-		filepath, _ := cmd.Flags().GetString(app.ConfigFileFlag)
-
-		cfg, err := config.LoadYaml[app.Config](filepath)
+		cfg, err := loadConfig(cmd)
 		if err != nil {
-			return fmt.Errorf("loading config: %w", err)
+			return err
 		}
 
-		if err := cfg.Check(); err != nil {
-			return fmt.Errorf("checking config: %w", err)
+		dryRun, _ := cmd.Flags().GetBool(app.DryRunFlag)
+
+		var w astfile.Writer = astfile.NewDisk()
+
+		if dryRun {
+			w = astfile.NewPrinter(cmd.OutOrStdout())
 		}
 
-		gen := generator.New(cfg, reporter.NewLog(l))
+		gen := generator.New(cfg, reporter.NewLog(l), w)
 
 		if err := gen.Generate(); err != nil {
 			return fmt.Errorf("generating interfaces: %w", err)
 		}
 
+		if dryRun {
+			l.Info("dry run completed, nothing was written")
+
+			return nil
+		}
+
 		l.Info("interfaces generated successfully")
 
 		banner()
@@ -21,8 +21,9 @@ func main() {
 	root := command.NewRoot(runnable.NewRoot(l))
 	version := version.NewCommand(root.OutOrStderr())
 	init := command.NewInit(runnable.NewInit(l))
+	check := command.NewCheck(runnable.NewCheck(l))
 
-	root.AddCommand(version, init)
+	root.AddCommand(version, init, check)
 
 	if err := root.Execute(); err != nil {
 		os.Exit(1)
@@ -0,0 +1,96 @@
+package astfile
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"github.com/nuvrel/moldable/internal/typeast"
+)
+
+// buildAssertions emits a single var block holding one
+// var _ Iface = (*pkg.Struct)(nil) line per interface with an assertion.
+func (f *File) buildAssertions(qual types.Qualifier) ([]ast.Decl, error) {
+	specs := make([]ast.Spec, 0, len(f.interfaces))
+
+	for _, is := range f.interfaces {
+		// a rewritten interface is implemented by the adapter, which asserts
+		// it on its own, and no longer by the source struct
+		if is.Assertion == nil || is.Source == nil || f.rewritten(is) {
+			continue
+		}
+
+		spec, err := buildAssertion(is, qual)
+		if err != nil {
+			return nil, fmt.Errorf("building assertion for %q: %w", is.Name, err)
+		}
+
+		specs = append(specs, spec)
+	}
+
+	if len(specs) == 0 {
+		return nil, nil
+	}
+
+	return []ast.Decl{
+		&ast.GenDecl{
+			Tok:    token.VAR,
+			Lparen: 1,
+			Specs:  specs,
+		},
+	}, nil
+}
+
+func buildAssertion(is *InterfaceSpec, qual types.Qualifier) (ast.Spec, error) {
+	source := is.Source.Type()
+	args := make([]ast.Expr, len(is.Assertion.TypeArgs))
+
+	if len(args) > 0 {
+		inst, err := types.Instantiate(nil, source, is.Assertion.TypeArgs, true)
+		if err != nil {
+			return nil, fmt.Errorf("instantiating source type: %w", err)
+		}
+
+		source = inst
+	}
+
+	for i, arg := range is.Assertion.TypeArgs {
+		expr, err := typeast.Convert(arg, qual)
+		if err != nil {
+			return nil, fmt.Errorf("converting type arg %d: %w", i, err)
+		}
+
+		args[i] = expr
+	}
+
+	typ, err := typeast.Convert(source, qual)
+	if err != nil {
+		return nil, fmt.Errorf("converting source type: %w", err)
+	}
+
+	var value ast.Expr = &ast.CompositeLit{
+		Type: typ,
+	}
+
+	// only structs have a composite literal for every type; *new(T) is the
+	// zero value of anything else
+	if _, ok := source.Underlying().(*types.Struct); !ok {
+		value = &ast.StarExpr{X: call(ast.NewIdent("new"), typ)}
+	}
+
+	if is.Assertion.Pointer {
+		value = &ast.CallExpr{
+			Fun: &ast.ParenExpr{
+				X: &ast.StarExpr{X: typ},
+			},
+			Args: []ast.Expr{ast.NewIdent("nil")},
+		}
+	}
+
+	return &ast.ValueSpec{
+		Names:  []*ast.Ident{ast.NewIdent("_")},
+		Type:   typeast.Index(ast.NewIdent(is.Name), args),
+		Values: []ast.Expr{value},
+	}, nil
+}
@@ -0,0 +1,114 @@
+package astfile
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// Checker renders files in memory and compares them with what is on disk,
+// printing a unified diff for every file that is stale or missing. Nothing
+// is ever written to disk.
+type Checker struct {
+	out   io.Writer
+	stale []string
+}
+
+var (
+	_ Writer = (*Checker)(nil)
+)
+
+func NewChecker(out io.Writer) *Checker {
+	return &Checker{
+		out:   out,
+		stale: make([]string, 0),
+	}
+}
+
+func (c *Checker) Write(fset *token.FileSet, file *ast.File, path string) (bool, error) {
+	want, err := Render(fset, file, path)
+	if err != nil {
+		return false, fmt.Errorf("rendering file: %w", err)
+	}
+
+	return c.compare(path, want)
+}
+
+// Remove reports a file that would be removed as stale.
+func (c *Checker) Remove(path string) error {
+	_, err := c.compare(path, nil)
+
+	return err
+}
+
+func (c *Checker) Persists() bool {
+	return false
+}
+
+func (c *Checker) compare(path string, want []byte) (bool, error) {
+	from, to := path, path
+
+	if want == nil {
+		to = "/dev/null"
+	}
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			return false, fmt.Errorf("reading file from disk: %w", err)
+		}
+
+		from = "/dev/null"
+	}
+
+	if bytes.Equal(got, want) {
+		return false, nil
+	}
+
+	c.stale = append(c.stale, path)
+
+	diff := difflib.UnifiedDiff{
+		A:        splitLines(got),
+		B:        splitLines(want),
+		FromFile: from,
+		ToFile:   to,
+		Context:  3,
+	}
+
+	if err := difflib.WriteUnifiedDiff(c.out, diff); err != nil {
+		return false, fmt.Errorf("writing diff: %w", err)
+	}
+
+	return true, nil
+}
+
+// splitLines splits data after every newline. Unlike difflib.SplitLines, it
+// does not append an extra "\n" line, which would show up as a bogus change
+// at the end of every diff. A last line without a newline is marked the way
+// diff does, instead of running into the next line of the output.
+func splitLines(data []byte) []string {
+	lines := strings.SplitAfter(string(data), "\n")
+
+	last := len(lines) - 1
+
+	if lines[last] == "" {
+		return lines[:last]
+	}
+
+	lines[last] += "\n\\ No newline at end of file\n"
+
+	return lines
+}
+
+// Stale returns the paths whose content on disk differs from the generated
+// one, in the order they were checked.
+func (c *Checker) Stale() []string {
+	return c.stale
+}
@@ -0,0 +1,143 @@
+package astfile_test
+
+import (
+	"bytes"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nuvrel/moldable/internal/astfile"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const generated = `package p
+
+func A() {}
+`
+
+func TestChecker(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name   string
+		disk   *string
+		remove bool
+		stale  bool
+		diff   string
+	}{
+		{
+			name:  "up to date",
+			disk:  ptr(generated),
+			stale: false,
+		},
+		{
+			name:  "missing",
+			stale: true,
+			diff: `--- /dev/null
++++ {path}
+@@ -0,0 +1,3 @@
++package p
++
++func A() {}
+`,
+		},
+		{
+			name:  "stale",
+			disk:  ptr("package p\n\nfunc B() {}\n"),
+			stale: true,
+			diff: `--- {path}
++++ {path}
+@@ -1,3 +1,3 @@
+ package p
+ 
+-func B() {}
++func A() {}
+`,
+		},
+		{
+			name:  "no trailing newline",
+			disk:  ptr("package p\n\nfunc A() {}"),
+			stale: true,
+			diff: `--- {path}
++++ {path}
+@@ -1,3 +1,3 @@
+ package p
+ 
+-func A() {}
+\ No newline at end of file
++func A() {}
+`,
+		},
+		{
+			name:   "removed",
+			disk:   ptr(generated),
+			remove: true,
+			stale:  true,
+			diff: `--- {path}
++++ /dev/null
+@@ -1,3 +0,0 @@
+-package p
+-
+-func A() {}
+`,
+		},
+		{
+			name:   "removed and missing",
+			remove: true,
+			stale:  false,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), "p.go")
+
+			if c.disk != nil {
+				require.NoError(t, os.WriteFile(path, []byte(*c.disk), 0644))
+			}
+
+			var out bytes.Buffer
+
+			checker := astfile.NewChecker(&out)
+
+			if c.remove {
+				require.NoError(t, checker.Remove(path))
+			} else {
+				fset := token.NewFileSet()
+
+				file, err := parser.ParseFile(fset, path, generated, parser.ParseComments)
+				require.NoError(t, err)
+
+				changed, err := checker.Write(fset, file, path)
+				require.NoError(t, err)
+
+				assert.Equal(t, c.stale, changed)
+			}
+
+			assert.Equal(t, strings.ReplaceAll(c.diff, "{path}", path), out.String())
+
+			if c.stale {
+				assert.Equal(t, []string{path}, checker.Stale())
+			} else {
+				assert.Empty(t, checker.Stale())
+			}
+
+			if c.disk != nil {
+				got, err := os.ReadFile(path)
+				require.NoError(t, err)
+
+				assert.Equal(t, *c.disk, string(got), "the checker must not touch the disk")
+			}
+		})
+	}
+}
+
+func ptr(s string) *string {
+	return &s
+}
@@ -0,0 +1,315 @@
+package astfile
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"github.com/nuvrel/moldable/internal/typeast"
+)
+
+// collectTargets maps the source struct of every non-generic interface of the
+// file to it. In deep mode, params and results of type *Struct are declared
+// with the interface of Struct instead.
+func (f *File) collectTargets() map[*types.TypeName]*InterfaceSpec {
+	targets := make(map[*types.TypeName]*InterfaceSpec)
+
+	for _, is := range f.interfaces {
+		if is.Source != nil && is.TypeParams == nil {
+			targets[is.Source] = is
+		}
+	}
+
+	return targets
+}
+
+// target returns the interface replacing typ in deep mode, or nil. Only
+// pointers to structs are replaced, which the adapters can wrap and unwrap.
+func (f *File) target(typ types.Type) *InterfaceSpec {
+	ptr, ok := types.Unalias(typ).(*types.Pointer)
+	if !ok {
+		return nil
+	}
+
+	named, ok := types.Unalias(ptr.Elem()).(*types.Named)
+	if !ok {
+		return nil
+	}
+
+	return f.targets[named.Obj()]
+}
+
+// rewriteSignature replaces the types of the params and results of ft, the
+// converted form of sig, that have a target, and returns the targets by
+// position.
+func (f *File) rewriteSignature(ft *ast.FuncType, sig *types.Signature) ([]*InterfaceSpec, []*InterfaceSpec) {
+	rewrite := func(fl *ast.FieldList, tup *types.Tuple) []*InterfaceSpec {
+		targets := make([]*InterfaceSpec, tup.Len())
+
+		for i := range tup.Len() {
+			if t := f.target(tup.At(i).Type()); t != nil {
+				fl.List[i].Type = ast.NewIdent(t.Name)
+				targets[i] = t
+			}
+		}
+
+		return targets
+	}
+
+	return rewrite(ft.Params, sig.Params()), rewrite(ft.Results, sig.Results())
+}
+
+// rewritten reports whether the interface declares a method whose signature
+// differs from the source struct because of deep mode.
+func (f *File) rewritten(is *InterfaceSpec) bool {
+	for _, m := range is.Methods {
+		sig, ok := m.Type().(*types.Signature)
+		if !ok {
+			continue
+		}
+
+		for _, tup := range []*types.Tuple{sig.Params(), sig.Results()} {
+			for i := range tup.Len() {
+				if f.target(tup.At(i).Type()) != nil {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+// adapterName returns the type wrapping the source struct of is. The delegate
+// doubles as the adapter when both are emitted, as they would be identical.
+func (f *File) adapterName(is *InterfaceSpec) string {
+	if f.options.Delegate {
+		return delegateName(is)
+	}
+
+	return is.Name + "Adapter"
+}
+
+// buildAdapters emits, for every interface, an adapter implementing it on top
+// of a pointer to its source struct, wrapping the results and unwrapping the
+// params deep mode rewrote, so the real implementation can be used where the
+// rewritten interfaces are expected.
+func (f *File) buildAdapters(qual types.Qualifier) ([]ast.Decl, error) {
+	decls := make([]ast.Decl, 0)
+
+	for _, is := range f.interfaces {
+		if is.Source == nil {
+			continue
+		}
+
+		d, err := f.buildAdapter(is, qual)
+		if err != nil {
+			return nil, fmt.Errorf("building adapter for %q: %w", is.Name, err)
+		}
+
+		decls = append(decls, d...)
+	}
+
+	return decls, nil
+}
+
+func (f *File) buildAdapter(is *InterfaceSpec, qual types.Qualifier) ([]ast.Decl, error) {
+	name := f.adapterName(is)
+
+	tp, err := typeast.ConvertTypeParams(is.TypeParams, qual)
+	if err != nil {
+		return nil, fmt.Errorf("converting type params: %w", err)
+	}
+
+	inner := &ast.StarExpr{X: typeast.Index(qualified(qual, is.Source.Pkg(), is.Source.Name()), typeParamNames(tp))}
+
+	decls := []ast.Decl{
+		typeDecl(name, tp, structType([]*ast.Field{
+			{Names: []*ast.Ident{ast.NewIdent("Inner")}, Type: inner},
+		})),
+		adapterConstructor(is, name, tp, inner),
+	}
+
+	if is.TypeParams == nil {
+		decls = append(decls, implementsDecl(is.Name, name))
+	}
+
+	for _, fn := range is.Methods {
+		sig, ok := fn.Type().(*types.Signature)
+		if !ok {
+			return nil, fmt.Errorf("method %q is not a function", fn.Name())
+		}
+
+		m, err := f.method(fn, qual, adapterLocals("a", sig)...)
+		if err != nil {
+			return nil, err
+		}
+
+		body, err := f.adapterBody(m, sig, sel(ast.NewIdent("a"), "Inner", m.name), qual)
+		if err != nil {
+			return nil, fmt.Errorf("building method %q: %w", m.name, err)
+		}
+
+		decls = append(decls, funcDecl("a", name, tp, m.name, m.unnamedSignature(), body...))
+	}
+
+	return decls, nil
+}
+
+// adapterConstructor returns New<name>, which wraps a pointer to the source
+// struct of is, keeping nil pointers nil interfaces.
+func adapterConstructor(is *InterfaceSpec, name string, tp *ast.FieldList, inner ast.Expr) *ast.FuncDecl {
+	args := typeParamNames(tp)
+
+	return &ast.FuncDecl{
+		Name: ast.NewIdent("New" + name),
+		Type: &ast.FuncType{
+			TypeParams: tp,
+			Params: &ast.FieldList{List: []*ast.Field{
+				{Names: []*ast.Ident{ast.NewIdent("v")}, Type: inner},
+			}},
+			Results: &ast.FieldList{List: []*ast.Field{
+				{Type: typeast.Index(ast.NewIdent(is.Name), args)},
+			}},
+		},
+		Body: &ast.BlockStmt{List: []ast.Stmt{
+			&ast.IfStmt{
+				Cond: &ast.BinaryExpr{X: ast.NewIdent("v"), Op: token.EQL, Y: ast.NewIdent("nil")},
+				Body: &ast.BlockStmt{List: []ast.Stmt{
+					&ast.ReturnStmt{Results: idents("nil")},
+				}},
+			},
+			&ast.ReturnStmt{Results: []ast.Expr{&ast.UnaryExpr{
+				Op: token.AND,
+				X: &ast.CompositeLit{
+					Type: typeast.Index(ast.NewIdent(name), args),
+					Elts: []ast.Expr{&ast.KeyValueExpr{Key: ast.NewIdent("Inner"), Value: ast.NewIdent("v")}},
+				},
+			}}},
+		}},
+	}
+}
+
+// adapterLocals returns the receiver and the locals declared by adapterBody,
+// which the params of a method built with it must not shadow.
+func adapterLocals(recv string, sig *types.Signature) []string {
+	locals := []string{recv, "v", "ok"}
+
+	for i := range sig.Params().Len() {
+		locals = append(locals, fmt.Sprintf("in%d", i))
+	}
+
+	for i := range sig.Results().Len() {
+		locals = append(locals, fmt.Sprintf("r%d", i))
+	}
+
+	return locals
+}
+
+// adapterBody forwards the call to fn, usually the method of the inner struct,
+// unwrapping the params and wrapping the results deep mode rewrote:
+//
+//	var in0 *pkg.T
+//	if p != nil {
+//		v, ok := p.(*TIfaceAdapter)
+//		if !ok {
+//			panic("M: p must be a *TIfaceAdapter to unwrap the *pkg.T it holds")
+//		}
+//		in0 = v.Inner
+//	}
+//	r0, r1 := a.Inner.M(in0)
+//	return NewUIfaceAdapter(r0), r1
+//
+// Unwrapping panics when given an implementation other than the adapter,
+// such as a mock, as the struct cannot be recovered from it. The adapter is
+// the delegate when delegates are emitted.
+func (f *File) adapterBody(m *method, sig *types.Signature, fn ast.Expr, qual types.Qualifier) ([]ast.Stmt, error) {
+	body := make([]ast.Stmt, 0)
+
+	names := m.paramNames()
+	args := idents(names...)
+
+	for i, t := range m.paramTargets {
+		if t == nil {
+			continue
+		}
+
+		typ, err := typeast.Convert(sig.Params().At(i).Type(), qual)
+		if err != nil {
+			return nil, fmt.Errorf("converting param %d: %w", i, err)
+		}
+
+		in := ast.NewIdent(fmt.Sprintf("in%d", i))
+		param := ast.NewIdent(names[i])
+
+		body = append(body,
+			&ast.DeclStmt{Decl: &ast.GenDecl{
+				Tok:   token.VAR,
+				Specs: []ast.Spec{&ast.ValueSpec{Names: []*ast.Ident{in}, Type: typ}},
+			}},
+			&ast.IfStmt{
+				Cond: &ast.BinaryExpr{X: param, Op: token.NEQ, Y: ast.NewIdent("nil")},
+				Body: &ast.BlockStmt{List: []ast.Stmt{
+					assign(token.DEFINE, idents("v", "ok"), &ast.TypeAssertExpr{
+						X:    param,
+						Type: &ast.StarExpr{X: ast.NewIdent(f.adapterName(t))},
+					}),
+					&ast.IfStmt{
+						Cond: &ast.UnaryExpr{Op: token.NOT, X: ast.NewIdent("ok")},
+						Body: &ast.BlockStmt{List: []ast.Stmt{
+							exprStmt(call(ast.NewIdent("panic"), str(fmt.Sprintf(
+								"%s: %s must be a *%s to unwrap the %s it holds",
+								m.name, names[i], f.adapterName(t), types.TypeString(sig.Params().At(i).Type(), qual),
+							)))),
+						}},
+					},
+					assign(token.ASSIGN, []ast.Expr{in}, sel(ast.NewIdent("v"), "Inner")),
+				}},
+			},
+		)
+
+		args[i] = in
+	}
+
+	forward := &ast.CallExpr{
+		Fun:  fn,
+		Args: args,
+	}
+
+	if m.variadic {
+		forward.Ellipsis = 1
+	}
+
+	wraps := false
+
+	for _, t := range m.resultTargets {
+		wraps = wraps || t != nil
+	}
+
+	switch {
+	case len(m.resultTargets) == 0:
+		body = append(body, exprStmt(forward))
+	case !wraps:
+		body = append(body, &ast.ReturnStmt{Results: []ast.Expr{forward}})
+	default:
+		rets := make([]ast.Expr, len(m.resultTargets))
+		results := make([]ast.Expr, len(m.resultTargets))
+
+		for i, t := range m.resultTargets {
+			rets[i] = ast.NewIdent(fmt.Sprintf("r%d", i))
+			results[i] = rets[i]
+
+			if t != nil {
+				results[i] = call(ast.NewIdent("New"+f.adapterName(t)), rets[i])
+			}
+		}
+
+		body = append(body,
+			assign(token.DEFINE, rets, forward),
+			&ast.ReturnStmt{Results: results},
+		)
+	}
+
+	return body, nil
+}
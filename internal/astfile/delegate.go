@@ -0,0 +1,84 @@
+package astfile
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+
+	"github.com/nuvrel/moldable/internal/typeast"
+)
+
+// buildDelegates emits, for every interface, a struct named after it that
+// holds a pointer to the source struct in Inner and forwards every method to
+// it. Embedding the delegate lets decorators override only the methods they
+// care about. In deep mode, the delegate takes the place of the adapter: it
+// gets its constructor and unwraps and wraps params and results the same
+// way, so it still implements the rewritten interface.
+func (f *File) buildDelegates(qual types.Qualifier) ([]ast.Decl, error) {
+	decls := make([]ast.Decl, 0)
+
+	for _, is := range f.interfaces {
+		if is.Source == nil {
+			continue
+		}
+
+		d, err := f.buildDelegate(is, qual)
+		if err != nil {
+			return nil, fmt.Errorf("building delegate for %q: %w", is.Name, err)
+		}
+
+		decls = append(decls, d...)
+	}
+
+	return decls, nil
+}
+
+func delegateName(is *InterfaceSpec) string {
+	return is.Name + "Delegate"
+}
+
+func (f *File) buildDelegate(is *InterfaceSpec, qual types.Qualifier) ([]ast.Decl, error) {
+	name := delegateName(is)
+
+	tp, err := typeast.ConvertTypeParams(is.TypeParams, qual)
+	if err != nil {
+		return nil, fmt.Errorf("converting type params: %w", err)
+	}
+
+	inner := &ast.StarExpr{X: typeast.Index(qualified(qual, is.Source.Pkg(), is.Source.Name()), typeParamNames(tp))}
+
+	decls := []ast.Decl{
+		typeDecl(name, tp, structType([]*ast.Field{
+			{Names: []*ast.Ident{ast.NewIdent("Inner")}, Type: inner},
+		})),
+	}
+
+	if f.options.Deep {
+		decls = append(decls, adapterConstructor(is, name, tp, inner))
+	}
+
+	if is.TypeParams == nil {
+		decls = append(decls, implementsDecl(is.Name, name))
+	}
+
+	for _, fn := range is.Methods {
+		sig, ok := fn.Type().(*types.Signature)
+		if !ok {
+			return nil, fmt.Errorf("method %q is not a function", fn.Name())
+		}
+
+		m, err := f.method(fn, qual, adapterLocals("d", sig)...)
+		if err != nil {
+			return nil, err
+		}
+
+		body, err := f.adapterBody(m, sig, sel(ast.NewIdent("d"), "Inner", m.name), qual)
+		if err != nil {
+			return nil, fmt.Errorf("building method %q: %w", m.name, err)
+		}
+
+		decls = append(decls, funcDecl("d", name, tp, m.name, m.signature(), body...))
+	}
+
+	return decls, nil
+}
@@ -0,0 +1,59 @@
+package astfile
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+)
+
+// buildFacades emits, for every facade interface, a default implementation
+// named Default followed by the facade name, forwarding each method to the
+// package-level function it comes from.
+func (f *File) buildFacades(qual types.Qualifier) ([]ast.Decl, error) {
+	decls := make([]ast.Decl, 0)
+
+	for _, is := range f.interfaces {
+		if is.Source != nil {
+			continue
+		}
+
+		d, err := f.buildFacade(is, qual)
+		if err != nil {
+			return nil, fmt.Errorf("building default implementation for %q: %w", is.Name, err)
+		}
+
+		decls = append(decls, d...)
+	}
+
+	return decls, nil
+}
+
+func (f *File) buildFacade(is *InterfaceSpec, qual types.Qualifier) ([]ast.Decl, error) {
+	name := "Default" + is.Facade
+
+	decls := []ast.Decl{
+		typeDecl(name, nil, structType(nil)),
+		implementsDecl(is.Name, name),
+	}
+
+	for _, fn := range is.Methods {
+		sig, ok := fn.Type().(*types.Signature)
+		if !ok {
+			return nil, fmt.Errorf("function %q is not a function", fn.Name())
+		}
+
+		m, err := f.method(fn, qual, adapterLocals("d", sig)...)
+		if err != nil {
+			return nil, err
+		}
+
+		body, err := f.adapterBody(m, sig, qualified(qual, fn.Pkg(), fn.Name()), qual)
+		if err != nil {
+			return nil, fmt.Errorf("building method %q: %w", m.name, err)
+		}
+
+		decls = append(decls, funcDecl("d", name, nil, m.name, m.unnamedSignature(), body...))
+	}
+
+	return decls, nil
+}
@@ -6,32 +6,124 @@ import (
 	"go/token"
 	"go/types"
 	"path/filepath"
+	"slices"
 
 	"github.com/nuvrel/moldable/internal/typeast"
 )
 
+// Header marks every file produced by moldable, which is also how files
+// owned by moldable are recognised when pruning.
+const Header = "// Code generated by moldable; DO NOT EDIT."
+
 type ImportSpec struct {
 	Path  string
 	Alias string
 }
 
+// InterfaceSpec describes a generated interface. Methods lists every method
+// of the interface, including the ones provided by the interfaces in Embeds
+// and by the interfaces of the same file in Includes, which are declared as
+// embedded types instead of being repeated.
+//
+// Facade interfaces gather package-level functions instead of the methods of
+// a Source type, which is then nil, and are named after Facade.
 type InterfaceSpec struct {
 	Name       string
+	Source     *types.TypeName
+	Facade     string
 	TypeParams *types.TypeParamList
 	Methods    []*types.Func
+	Embeds     []types.Type
+	Includes   []*InterfaceSpec
+	Assertion  *AssertionSpec
+}
+
+// baseName returns the name of the source type, or the facade name, which
+// the generated implementations mention in their messages.
+func (is *InterfaceSpec) baseName() string {
+	if is.Source == nil {
+		return is.Facade
+	}
+
+	return is.Source.Name()
+}
+
+// embedded reports whether the method m is provided by one of the embedded
+// interfaces.
+func (is *InterfaceSpec) embedded(m *types.Func) bool {
+	for _, inc := range is.Includes {
+		if slices.Contains(inc.Methods, m) {
+			return true
+		}
+	}
+
+	for _, e := range is.Embeds {
+		if obj, _, _ := types.LookupFieldOrMethod(e, false, m.Pkg(), m.Name()); obj != nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AssertionSpec describes how to assert at compile time that the source type
+// still implements the interface. TypeArgs instantiate generic sources and
+// Pointer tells whether the method set requires a pointer receiver.
+type AssertionSpec struct {
+	TypeArgs []types.Type
+	Pointer  bool
+}
+
+type Options struct {
+	Assertions    bool
+	Moq           bool
+	Gomock        bool
+	Testify       bool
+	Delegate      bool
+	Instrument    bool
+	Noop          bool
+	Unimplemented bool
+	Deep          bool
+}
+
+// Packages returns the packages the enabled options reference, which must be
+// imported for the qualifier passed to Build to know them.
+func (o Options) Packages() []*types.Package {
+	pkgs := make([]*types.Package, 0)
+
+	if o.Moq {
+		pkgs = append(pkgs, syncPackage)
+	}
+
+	if o.Gomock {
+		pkgs = append(pkgs, gomockPackage, reflectPackage)
+	}
+
+	if o.Testify {
+		pkgs = append(pkgs, testifyPackage)
+	}
+
+	if o.Instrument {
+		pkgs = append(pkgs, timePackage)
+	}
+
+	return pkgs
 }
 
 type File struct {
 	fset        *token.FileSet
 	packageName string
+	options     Options
 	imports     []*ImportSpec
 	interfaces  []*InterfaceSpec
+	targets     map[*types.TypeName]*InterfaceSpec
 }
 
-func New(fset *token.FileSet, packageName string) *File {
+func New(fset *token.FileSet, packageName string, opts Options) *File {
 	return &File{
 		fset:        fset,
 		packageName: packageName,
+		options:     opts,
 		imports:     make([]*ImportSpec, 0),
 		interfaces:  make([]*InterfaceSpec, 0),
 	}
@@ -52,21 +144,114 @@ func (f *File) AddInterface(spec *InterfaceSpec) {
 func (f *File) Build(qual types.Qualifier) (*ast.File, error) {
 	imports := f.buildImports()
 
+	if f.options.Deep {
+		f.targets = f.collectTargets()
+	}
+
 	interfaces, err := f.buildInterfaces(qual)
 	if err != nil {
 		return nil, fmt.Errorf("building interface declarations: %w", err)
 	}
 
-	decls := make([]ast.Decl, 0, len(imports)+len(interfaces))
+	decls := make([]ast.Decl, 0, len(imports)+len(interfaces)+1)
 
 	decls = append(decls, imports...)
 	decls = append(decls, interfaces...)
 
+	if f.options.Assertions {
+		assertions, err := f.buildAssertions(qual)
+		if err != nil {
+			return nil, fmt.Errorf("building assertions: %w", err)
+		}
+
+		decls = append(decls, assertions...)
+	}
+
+	facades, err := f.buildFacades(qual)
+	if err != nil {
+		return nil, fmt.Errorf("building facade implementations: %w", err)
+	}
+
+	decls = append(decls, facades...)
+
+	if f.options.Moq {
+		moqs, err := f.buildMoqs(qual)
+		if err != nil {
+			return nil, fmt.Errorf("building moq mocks: %w", err)
+		}
+
+		decls = append(decls, moqs...)
+	}
+
+	if f.options.Gomock {
+		gomocks, err := f.buildGomocks(qual)
+		if err != nil {
+			return nil, fmt.Errorf("building gomock mocks: %w", err)
+		}
+
+		decls = append(decls, gomocks...)
+	}
+
+	if f.options.Testify {
+		testify, err := f.buildTestifyMocks(qual)
+		if err != nil {
+			return nil, fmt.Errorf("building testify mocks: %w", err)
+		}
+
+		decls = append(decls, testify...)
+	}
+
+	if f.options.Delegate {
+		delegates, err := f.buildDelegates(qual)
+		if err != nil {
+			return nil, fmt.Errorf("building delegates: %w", err)
+		}
+
+		decls = append(decls, delegates...)
+	}
+
+	if f.options.Instrument {
+		instrumented, err := f.buildInstrumented(qual)
+		if err != nil {
+			return nil, fmt.Errorf("building instrumented decorators: %w", err)
+		}
+
+		decls = append(decls, instrumented...)
+	}
+
+	if f.options.Noop {
+		noops, err := f.buildStubs(noop, qual)
+		if err != nil {
+			return nil, fmt.Errorf("building noop implementations: %w", err)
+		}
+
+		decls = append(decls, noops...)
+	}
+
+	if f.options.Unimplemented {
+		stubs, err := f.buildStubs(unimplemented, qual)
+		if err != nil {
+			return nil, fmt.Errorf("building unimplemented implementations: %w", err)
+		}
+
+		decls = append(decls, stubs...)
+	}
+
+	// the delegates already wrap the source structs the way adapters would
+	if f.options.Deep && !f.options.Delegate {
+		adapters, err := f.buildAdapters(qual)
+		if err != nil {
+			return nil, fmt.Errorf("building adapters: %w", err)
+		}
+
+		decls = append(decls, adapters...)
+	}
+
 	return &ast.File{
 		Doc: &ast.CommentGroup{
 			List: []*ast.Comment{{
 				Slash: 1,
-				Text:  "// Code generated by moldable; DO NOT EDIT.",
+				Text:  Header,
 			}},
 		},
 		Package: 2,
@@ -109,15 +294,42 @@ func (f *File) buildImports() []ast.Decl {
 func (f *File) buildInterfaces(qual types.Qualifier) ([]ast.Decl, error) {
 	decls := make([]ast.Decl, 0, len(f.interfaces))
 
+	reserved := f.qualifiers()
+
 	for _, is := range f.interfaces {
-		methods := make([]*ast.Field, 0, len(is.Methods))
+		methods := make([]*ast.Field, 0, len(is.Includes)+len(is.Embeds)+len(is.Methods))
+
+		for _, inc := range is.Includes {
+			methods = append(methods, &ast.Field{Type: ast.NewIdent(inc.Name)})
+		}
+
+		for _, e := range is.Embeds {
+			expr, err := typeast.Convert(e, qual)
+			if err != nil {
+				return nil, fmt.Errorf("converting embedded interface %s: %w", e, err)
+			}
+
+			methods = append(methods, &ast.Field{Type: expr})
+		}
 
 		for _, m := range is.Methods {
+			if is.embedded(m) {
+				continue
+			}
+
 			expr, err := typeast.Convert(m.Type(), qual)
 			if err != nil {
 				return nil, fmt.Errorf("converting method %q type: %w", m.Name(), err)
 			}
 
+			if ft, ok := expr.(*ast.FuncType); ok {
+				if sig, ok := m.Type().(*types.Signature); ok {
+					f.rewriteSignature(ft, sig)
+				}
+
+				renameParams(ft, reserved)
+			}
+
 			methods = append(methods, &ast.Field{
 				Names: []*ast.Ident{
 					ast.NewIdent(m.Name()),
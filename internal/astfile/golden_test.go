@@ -0,0 +1,250 @@
+package astfile_test
+
+import (
+	"flag"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/nuvrel/moldable/internal/astfile"
+	"github.com/nuvrel/moldable/internal/importset"
+	"github.com/nuvrel/moldable/internal/typeast"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	// the generated mocks are type-checked against the real gomock package,
+	// which therefore has to be required by go.mod
+	_ "go.uber.org/mock/gomock"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// sourceImporter type-checks the standard library and the module dependencies
+// from source. It caches packages, which keeps their identity the same for
+// the fixture and the generated files, but is not safe for concurrent use.
+var sourceImporter = struct {
+	sync.Mutex
+	types.Importer
+}{
+	Importer: importer.ForCompiler(token.NewFileSet(), "source", nil),
+}
+
+type importerFunc func(path string) (*types.Package, error)
+
+func (f importerFunc) Import(path string) (*types.Package, error) {
+	return f(path)
+}
+
+// importing resolves the path of pkg to pkg and every other path from source.
+func importing(pkg *types.Package) types.Importer {
+	return importerFunc(func(path string) (*types.Package, error) {
+		if pkg != nil && path == pkg.Path() {
+			return pkg, nil
+		}
+
+		sourceImporter.Lock()
+		defer sourceImporter.Unlock()
+
+		return sourceImporter.Import(path)
+	})
+}
+
+func check(t *testing.T, path, filename string, src []byte, imp types.Importer) *types.Package {
+	t.Helper()
+
+	fset := token.NewFileSet()
+
+	file, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+	require.NoError(t, err)
+
+	conf := types.Config{Importer: imp}
+
+	pkg, err := conf.Check(path, fset, []*ast.File{file}, nil)
+	require.NoError(t, err, "type-checking %s", filename)
+
+	return pkg
+}
+
+func loadFixture(t *testing.T) *types.Package {
+	t.Helper()
+
+	filename := filepath.Join("testdata", "fixture", "fixture.go")
+
+	src, err := os.ReadFile(filename)
+	require.NoError(t, err)
+
+	return check(t, "example.com/fixture", filename, src, importing(nil))
+}
+
+// specsFunc builds the interfaces of a golden file from the fixture package.
+type specsFunc func(t *testing.T, pkg *types.Package) []*astfile.InterfaceSpec
+
+// sources returns the interfaces generated from the named fixture types, the
+// way the generator builds them.
+func sources(names ...string) specsFunc {
+	return func(t *testing.T, pkg *types.Package) []*astfile.InterfaceSpec {
+		specs := make([]*astfile.InterfaceSpec, 0, len(names))
+
+		for _, name := range names {
+			tn := pkg.Scope().Lookup(name).(*types.TypeName)
+			tp := tn.Type().(*types.Named).TypeParams()
+
+			args, err := typeast.SampleTypeArgs(tp)
+			require.NoError(t, err)
+
+			ms := types.NewMethodSet(types.NewPointer(tn.Type()))
+			values := types.NewMethodSet(tn.Type())
+
+			spec := &astfile.InterfaceSpec{
+				Name:       name + "Contract",
+				Source:     tn,
+				TypeParams: tp,
+				Assertion: &astfile.AssertionSpec{
+					TypeArgs: args,
+					Pointer:  values.Len() < ms.Len(),
+				},
+			}
+
+			for i := range ms.Len() {
+				spec.Methods = append(spec.Methods, ms.At(i).Obj().(*types.Func))
+			}
+
+			specs = append(specs, spec)
+		}
+
+		return specs
+	}
+}
+
+// functions returns a facade interface over the named fixture functions.
+func functions(facade string, names ...string) specsFunc {
+	return func(t *testing.T, pkg *types.Package) []*astfile.InterfaceSpec {
+		spec := &astfile.InterfaceSpec{
+			Name:   facade + "Contract",
+			Facade: facade,
+		}
+
+		for _, name := range names {
+			spec.Methods = append(spec.Methods, pkg.Scope().Lookup(name).(*types.Func))
+		}
+
+		return []*astfile.InterfaceSpec{spec}
+	}
+}
+
+// join returns the interfaces of every fn, in order.
+func join(fns ...specsFunc) specsFunc {
+	return func(t *testing.T, pkg *types.Package) []*astfile.InterfaceSpec {
+		specs := make([]*astfile.InterfaceSpec, 0)
+
+		for _, fn := range fns {
+			specs = append(specs, fn(t, pkg)...)
+		}
+
+		return specs
+	}
+}
+
+func render(t *testing.T, pkg *types.Package, specs []*astfile.InterfaceSpec, opts astfile.Options) []byte {
+	t.Helper()
+
+	fset := token.NewFileSet()
+
+	fset.AddFile("contract.go", fset.Base(), 1)
+
+	f := astfile.New(fset, "contract", opts)
+
+	is := importset.New()
+	is.Import(pkg)
+
+	for _, spec := range specs {
+		typeast.TraverseTypeParams(spec.TypeParams, is.Import)
+		typeast.TraverseFuncs(spec.Methods, is.Import)
+
+		f.AddInterface(spec)
+	}
+
+	for _, dep := range opts.Packages() {
+		is.Import(dep)
+	}
+
+	for path, alias := range is.Imports() {
+		f.AddImport(&astfile.ImportSpec{Path: path, Alias: alias})
+	}
+
+	file, err := f.Build(is.Qualifier)
+	require.NoError(t, err)
+
+	src, err := astfile.Render(fset, file, "contract.go")
+	require.NoError(t, err)
+
+	return src
+}
+
+// TestGolden renders fixture interfaces with each set of options, compares
+// the output with testdata/<name>.golden and type-checks it against the
+// fixture package. Run it with -update to rewrite the golden files.
+func TestGolden(t *testing.T) {
+	t.Parallel()
+
+	emitters := astfile.Options{
+		Assertions:    true,
+		Moq:           true,
+		Gomock:        true,
+		Testify:       true,
+		Delegate:      true,
+		Instrument:    true,
+		Noop:          true,
+		Unimplemented: true,
+	}
+
+	structs := sources("Store", "Box", "Point")
+
+	cases := []struct {
+		name  string
+		specs specsFunc
+		opts  astfile.Options
+	}{
+		{name: "assertions", specs: join(structs, sources("Handler")), opts: astfile.Options{Assertions: true}},
+		{name: "moq", specs: structs, opts: astfile.Options{Moq: true}},
+		{name: "gomock", specs: structs, opts: astfile.Options{Gomock: true}},
+		{name: "testify", specs: structs, opts: astfile.Options{Testify: true}},
+		{name: "delegate", specs: structs, opts: astfile.Options{Delegate: true}},
+		{name: "instrument", specs: structs, opts: astfile.Options{Instrument: true}},
+		{name: "noop", specs: structs, opts: astfile.Options{Noop: true}},
+		{name: "unimplemented", specs: structs, opts: astfile.Options{Unimplemented: true}},
+		{name: "deep", specs: structs, opts: astfile.Options{Assertions: true, Deep: true}},
+		{name: "deep_delegate", specs: structs, opts: astfile.Options{Delegate: true, Deep: true}},
+		{name: "kinds", specs: sources("Handler"), opts: emitters},
+		{name: "facade", specs: functions("Fixture", "Join", "Open", "Reset", "Swap"), opts: emitters},
+		{name: "facade_deep", specs: join(sources("Store"), functions("Fixture", "Open", "Swap")), opts: astfile.Options{Deep: true}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			pkg := loadFixture(t)
+			got := render(t, pkg, c.specs(t, pkg), c.opts)
+
+			golden := filepath.Join("testdata", c.name+".golden")
+
+			if *update {
+				require.NoError(t, os.WriteFile(golden, got, 0644))
+			}
+
+			want, err := os.ReadFile(golden)
+			require.NoError(t, err)
+
+			assert.Equal(t, string(want), string(got))
+
+			check(t, "example.com/contract", golden, got, importing(pkg))
+		})
+	}
+}
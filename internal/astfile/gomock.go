@@ -0,0 +1,235 @@
+package astfile
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"github.com/nuvrel/moldable/internal/typeast"
+)
+
+var (
+	gomockPackage  = types.NewPackage("go.uber.org/mock/gomock", "gomock")
+	reflectPackage = types.NewPackage("reflect", "reflect")
+)
+
+// buildGomocks emits, for every interface, the MockXxx and
+// MockXxxMockRecorder pair mockgen would produce, so the result plugs into
+// go.uber.org/mock without running mockgen.
+func (f *File) buildGomocks(qual types.Qualifier) ([]ast.Decl, error) {
+	decls := make([]ast.Decl, 0)
+
+	for _, is := range f.interfaces {
+		d, err := f.buildGomock(is, qual)
+		if err != nil {
+			return nil, fmt.Errorf("building gomock mock for %q: %w", is.Name, err)
+		}
+
+		decls = append(decls, d...)
+	}
+
+	return decls, nil
+}
+
+func (f *File) buildGomock(is *InterfaceSpec, qual types.Qualifier) ([]ast.Decl, error) {
+	mockName := "Mock" + is.Name
+	recorderName := mockName + "MockRecorder"
+
+	tp, err := typeast.ConvertTypeParams(is.TypeParams, qual)
+	if err != nil {
+		return nil, fmt.Errorf("converting type params: %w", err)
+	}
+
+	args := typeParamNames(tp)
+	mockType := &ast.StarExpr{X: typeast.Index(ast.NewIdent(mockName), args)}
+	recorderType := &ast.StarExpr{X: typeast.Index(ast.NewIdent(recorderName), args)}
+	controller := &ast.StarExpr{X: qualified(qual, gomockPackage, "Controller")}
+
+	decls := []ast.Decl{
+		typeDecl(mockName, tp, structType([]*ast.Field{
+			{Names: []*ast.Ident{ast.NewIdent("ctrl")}, Type: controller},
+			{Names: []*ast.Ident{ast.NewIdent("recorder")}, Type: recorderType},
+			{Names: []*ast.Ident{ast.NewIdent("isgomock")}, Type: structType(nil)},
+		})),
+		typeDecl(recorderName, tp, structType([]*ast.Field{
+			{Names: []*ast.Ident{ast.NewIdent("mock")}, Type: mockType},
+		})),
+		&ast.FuncDecl{
+			Name: ast.NewIdent("New" + mockName),
+			Type: &ast.FuncType{
+				TypeParams: tp,
+				Params: &ast.FieldList{List: []*ast.Field{
+					{Names: []*ast.Ident{ast.NewIdent("ctrl")}, Type: controller},
+				}},
+				Results: &ast.FieldList{List: []*ast.Field{{Type: mockType}}},
+			},
+			Body: &ast.BlockStmt{List: []ast.Stmt{
+				assign(token.DEFINE, idents("mock"), &ast.UnaryExpr{
+					Op: token.AND,
+					X: &ast.CompositeLit{
+						Type: typeast.Index(ast.NewIdent(mockName), args),
+						Elts: []ast.Expr{&ast.KeyValueExpr{Key: ast.NewIdent("ctrl"), Value: ast.NewIdent("ctrl")}},
+					},
+				}),
+				assign(token.ASSIGN, []ast.Expr{sel(ast.NewIdent("mock"), "recorder")}, &ast.UnaryExpr{
+					Op: token.AND,
+					X: &ast.CompositeLit{
+						Type: typeast.Index(ast.NewIdent(recorderName), args),
+						Elts: idents("mock"),
+					},
+				}),
+				&ast.ReturnStmt{Results: idents("mock")},
+			}},
+		},
+		funcDecl("m", mockName, tp, "EXPECT", &ast.FuncType{
+			Params:  &ast.FieldList{},
+			Results: &ast.FieldList{List: []*ast.Field{{Type: recorderType}}},
+		}, &ast.ReturnStmt{Results: []ast.Expr{sel(ast.NewIdent("m"), "recorder")}}),
+	}
+
+	for _, fn := range is.Methods {
+		locals := []string{"m", "mr", "ret", "varargs", "a"}
+
+		if sig, ok := fn.Type().(*types.Signature); ok {
+			for i := range sig.Results().Len() {
+				locals = append(locals, fmt.Sprintf("ret%d", i))
+			}
+		}
+
+		m, err := f.method(fn, qual, locals...)
+		if err != nil {
+			return nil, err
+		}
+
+		decls = append(decls,
+			gomockMethod(mockName, tp, m, qual),
+			gomockRecorder(mockName, recorderName, tp, m, qual),
+		)
+	}
+
+	return decls, nil
+}
+
+// helperCall builds x.ctrl.T.Helper().
+func helperCall(x ast.Expr) ast.Stmt {
+	return exprStmt(call(sel(x, "ctrl", "T", "Helper")))
+}
+
+// gomockMethod implements the interface method by forwarding it to the
+// controller and type asserting every returned value.
+func gomockMethod(mockName string, tp *ast.FieldList, m *method, qual types.Qualifier) ast.Decl {
+	recv := ast.NewIdent("m")
+	body := []ast.Stmt{helperCall(recv)}
+
+	callArgs := []ast.Expr{recv, str(m.name)}
+
+	if m.variadic {
+		body = append(body, m.spreadArgs()...)
+
+		callArgs = append(callArgs, ast.NewIdent("varargs"))
+	} else {
+		callArgs = append(callArgs, idents(m.paramNames()...)...)
+	}
+
+	ctrlCall := call(sel(recv, "ctrl", "Call"), callArgs...)
+
+	if m.variadic {
+		ctrlCall.Ellipsis = 1
+	}
+
+	results := m.resultTypes()
+
+	if len(results) == 0 {
+		body = append(body, exprStmt(ctrlCall))
+
+		return funcDecl("m", mockName, tp, m.name, m.unnamedSignature(), body...)
+	}
+
+	body = append(body, assign(token.DEFINE, idents("ret"), ctrlCall))
+
+	rets := make([]ast.Expr, len(results))
+
+	for i, typ := range results {
+		name := fmt.Sprintf("ret%d", i)
+
+		body = append(body, assign(token.DEFINE, idents(name, "_"), &ast.TypeAssertExpr{
+			X: &ast.IndexExpr{
+				X:     ast.NewIdent("ret"),
+				Index: &ast.BasicLit{Kind: token.INT, Value: fmt.Sprint(i)},
+			},
+			Type: typ,
+		}))
+
+		rets[i] = ast.NewIdent(name)
+	}
+
+	body = append(body, &ast.ReturnStmt{Results: rets})
+
+	return funcDecl("m", mockName, tp, m.name, m.unnamedSignature(), body...)
+}
+
+// gomockRecorder records an expected call of the method, taking matchers or
+// plain values for every param.
+func gomockRecorder(mockName, recorderName string, tp *ast.FieldList, m *method, qual types.Qualifier) ast.Decl {
+	recv := ast.NewIdent("mr")
+	mock := sel(recv, "mock")
+	names := m.paramNames()
+
+	params := make([]*ast.Field, len(names))
+
+	for i, n := range names {
+		var typ ast.Expr = ast.NewIdent("any")
+
+		if m.variadic && i == len(names)-1 {
+			typ = &ast.Ellipsis{Elt: ast.NewIdent("any")}
+		}
+
+		params[i] = &ast.Field{
+			Names: []*ast.Ident{ast.NewIdent(n)},
+			Type:  typ,
+		}
+	}
+
+	methodType := call(qualified(qual, reflectPackage, "TypeOf"), sel(&ast.CallExpr{
+		Fun: &ast.ParenExpr{
+			X: &ast.StarExpr{X: typeast.Index(ast.NewIdent(mockName), typeParamNames(tp))},
+		},
+		Args: idents("nil"),
+	}, m.name))
+
+	body := []ast.Stmt{exprStmt(call(sel(mock, "ctrl", "T", "Helper")))}
+
+	recordArgs := []ast.Expr{mock, str(m.name), methodType}
+
+	if m.variadic {
+		body = append(body, varargs(m, func(last ast.Expr) []ast.Stmt {
+			return []ast.Stmt{
+				assign(token.ASSIGN, idents("varargs"), &ast.CallExpr{
+					Fun:      ast.NewIdent("append"),
+					Args:     []ast.Expr{ast.NewIdent("varargs"), last},
+					Ellipsis: 1,
+				}),
+			}
+		})...)
+
+		recordArgs = append(recordArgs, ast.NewIdent("varargs"))
+	} else {
+		recordArgs = append(recordArgs, idents(names...)...)
+	}
+
+	record := call(sel(mock, "ctrl", "RecordCallWithMethodType"), recordArgs...)
+
+	if m.variadic {
+		record.Ellipsis = 1
+	}
+
+	body = append(body, &ast.ReturnStmt{Results: []ast.Expr{record}})
+
+	return funcDecl("mr", recorderName, tp, m.name, &ast.FuncType{
+		Params: &ast.FieldList{List: params},
+		Results: &ast.FieldList{List: []*ast.Field{
+			{Type: &ast.StarExpr{X: qualified(qual, gomockPackage, "Call")}},
+		}},
+	}, body...)
+}
@@ -0,0 +1,199 @@
+package astfile
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"github.com/nuvrel/moldable/internal/typeast"
+)
+
+var timePackage = types.NewPackage("time", "time")
+
+// buildInstrumented emits, for every interface, a hook interface and a
+// decorator wrapping any implementation of it, which calls the hook around
+// every method. Only the standard library is used, so logging, metrics and
+// tracing are plugged in by implementing the hook.
+func (f *File) buildInstrumented(qual types.Qualifier) ([]ast.Decl, error) {
+	decls := make([]ast.Decl, 0)
+
+	for _, is := range f.interfaces {
+		d, err := f.buildInstrumentedOne(is, qual)
+		if err != nil {
+			return nil, fmt.Errorf("building instrumented decorator for %q: %w", is.Name, err)
+		}
+
+		decls = append(decls, d...)
+	}
+
+	return decls, nil
+}
+
+func (f *File) buildInstrumentedOne(is *InterfaceSpec, qual types.Qualifier) ([]ast.Decl, error) {
+	name := "Instrumented" + is.Name
+	hook := is.Name + "Hook"
+
+	tp, err := typeast.ConvertTypeParams(is.TypeParams, qual)
+	if err != nil {
+		return nil, fmt.Errorf("converting type params: %w", err)
+	}
+
+	args := typeParamNames(tp)
+	iface := typeast.Index(ast.NewIdent(is.Name), args)
+	anys := &ast.ArrayType{Elt: ast.NewIdent("any")}
+
+	field := func(typ ast.Expr, names ...string) *ast.Field {
+		f := &ast.Field{Type: typ}
+
+		for _, n := range names {
+			f.Names = append(f.Names, ast.NewIdent(n))
+		}
+
+		return f
+	}
+
+	decls := []ast.Decl{
+		typeDecl(hook, nil, &ast.InterfaceType{
+			Methods: &ast.FieldList{List: []*ast.Field{
+				field(&ast.FuncType{
+					Params: &ast.FieldList{List: []*ast.Field{
+						field(ast.NewIdent("string"), "method"),
+						field(anys, "args"),
+					}},
+				}, "Before"),
+				field(&ast.FuncType{
+					Params: &ast.FieldList{List: []*ast.Field{
+						field(ast.NewIdent("string"), "method"),
+						field(anys, "args"),
+						field(ast.NewIdent("error"), "err"),
+						field(qualified(qual, timePackage, "Duration"), "elapsed"),
+					}},
+				}, "After"),
+			}},
+		}),
+		typeDecl(name, tp, structType([]*ast.Field{
+			field(iface, "Inner"),
+			field(ast.NewIdent(hook), "Hook"),
+		})),
+		&ast.FuncDecl{
+			Name: ast.NewIdent("New" + name),
+			Type: &ast.FuncType{
+				TypeParams: tp,
+				Params: &ast.FieldList{List: []*ast.Field{
+					field(iface, "inner"),
+					field(ast.NewIdent(hook), "hook"),
+				}},
+				Results: &ast.FieldList{List: []*ast.Field{
+					field(&ast.StarExpr{X: typeast.Index(ast.NewIdent(name), args)}),
+				}},
+			},
+			Body: &ast.BlockStmt{List: []ast.Stmt{
+				&ast.ReturnStmt{Results: []ast.Expr{&ast.UnaryExpr{
+					Op: token.AND,
+					X: &ast.CompositeLit{
+						Type: typeast.Index(ast.NewIdent(name), args),
+						Elts: []ast.Expr{
+							&ast.KeyValueExpr{Key: ast.NewIdent("Inner"), Value: ast.NewIdent("inner")},
+							&ast.KeyValueExpr{Key: ast.NewIdent("Hook"), Value: ast.NewIdent("hook")},
+						},
+					},
+				}}},
+			}},
+		},
+	}
+
+	if is.TypeParams == nil {
+		decls = append(decls, implementsDecl(is.Name, name))
+	}
+
+	for _, fn := range is.Methods {
+		locals := []string{"inst", "args", "start"}
+
+		sig, ok := fn.Type().(*types.Signature)
+		if !ok {
+			return nil, fmt.Errorf("method %q is not a function", fn.Name())
+		}
+
+		for i := range sig.Results().Len() {
+			locals = append(locals, fmt.Sprintf("r%d", i))
+		}
+
+		m, err := f.method(fn, qual, locals...)
+		if err != nil {
+			return nil, err
+		}
+
+		decls = append(decls, instrumentedMethod(name, tp, m, returnsError(sig), qual))
+	}
+
+	return decls, nil
+}
+
+// returnsError reports whether the last result of sig is an error, which is
+// then passed to the After hook.
+func returnsError(sig *types.Signature) bool {
+	results := sig.Results()
+
+	if results.Len() == 0 {
+		return false
+	}
+
+	return types.Identical(results.At(results.Len()-1).Type(), types.Universe.Lookup("error").Type())
+}
+
+// instrumentedMethod forwards the call to the inner implementation between
+// the hooks:
+//
+//	args := []any{...}
+//	inst.Hook.Before("M", args)
+//	start := time.Now()
+//	r0, r1 := inst.Inner.M(...)
+//	inst.Hook.After("M", args, r1, time.Since(start))
+//	return r0, r1
+func instrumentedMethod(name string, tp *ast.FieldList, m *method, withErr bool, qual types.Qualifier) ast.Decl {
+	inst := ast.NewIdent("inst")
+	method := str(m.name)
+
+	body := []ast.Stmt{
+		assign(token.DEFINE, idents("args"), &ast.CompositeLit{
+			Type: &ast.ArrayType{Elt: ast.NewIdent("any")},
+			Elts: idents(m.paramNames()...),
+		}),
+		exprStmt(call(sel(inst, "Hook", "Before"), method, ast.NewIdent("args"))),
+		assign(token.DEFINE, idents("start"), call(qualified(qual, timePackage, "Now"))),
+	}
+
+	forward := m.call(sel(inst, "Inner", m.name))
+	results := m.resultTypes()
+	rets := make([]ast.Expr, len(results))
+
+	for i := range results {
+		rets[i] = ast.NewIdent(fmt.Sprintf("r%d", i))
+	}
+
+	if len(rets) == 0 {
+		body = append(body, exprStmt(forward))
+	} else {
+		body = append(body, assign(token.DEFINE, rets, forward))
+	}
+
+	var err ast.Expr = ast.NewIdent("nil")
+
+	if withErr {
+		err = rets[len(rets)-1]
+	}
+
+	body = append(body, exprStmt(call(sel(inst, "Hook", "After"),
+		method,
+		ast.NewIdent("args"),
+		err,
+		call(qualified(qual, timePackage, "Since"), ast.NewIdent("start")),
+	)))
+
+	if len(rets) > 0 {
+		body = append(body, &ast.ReturnStmt{Results: rets})
+	}
+
+	return funcDecl("inst", name, tp, m.name, m.unnamedSignature(), body...)
+}
@@ -0,0 +1,386 @@
+package astfile
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"maps"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/nuvrel/moldable/internal/typeast"
+)
+
+// method is an interface method converted for use in generated bodies: every
+// param carries a unique name that shadows neither an import nor one of the
+// locals the body declares.
+//
+// In deep mode, params and results pointing to structs with a generated
+// interface use that interface instead, and the specs of those interfaces are
+// kept in paramTargets and resultTargets at the same positions.
+type method struct {
+	name          string
+	params        []*ast.Field
+	results       []*ast.Field
+	variadic      bool
+	paramTargets  []*InterfaceSpec
+	resultTargets []*InterfaceSpec
+}
+
+// method converts m as declared by the generated interface.
+func (f *File) method(m *types.Func, qual types.Qualifier, locals ...string) (*method, error) {
+	expr, err := typeast.Convert(m.Type(), qual)
+	if err != nil {
+		return nil, fmt.Errorf("converting method %q type: %w", m.Name(), err)
+	}
+
+	ft, ok := expr.(*ast.FuncType)
+	if !ok {
+		return nil, fmt.Errorf("method %q is not a function", m.Name())
+	}
+
+	sig, ok := m.Type().(*types.Signature)
+	if !ok {
+		return nil, fmt.Errorf("method %q is not a function", m.Name())
+	}
+
+	paramTargets, resultTargets := f.rewriteSignature(ft, sig)
+
+	reserved := f.qualifiers()
+
+	for _, l := range locals {
+		reserved[l] = true
+	}
+
+	renameParams(ft, reserved)
+
+	taken := make(map[string]bool)
+
+	maps.Copy(taken, reserved)
+
+	for _, field := range append(ft.Params.List, ft.Results.List...) {
+		for _, id := range field.Names {
+			taken[id.Name] = true
+		}
+	}
+
+	for i, field := range ft.Params.List {
+		for _, id := range field.Names {
+			if id.Name != "" && id.Name != "_" {
+				continue
+			}
+
+			id.Name = unique(fmt.Sprintf("arg%d", i), taken)
+		}
+	}
+
+	return &method{
+		name:          m.Name(),
+		params:        ft.Params.List,
+		results:       ft.Results.List,
+		variadic:      sig.Variadic(),
+		paramTargets:  paramTargets,
+		resultTargets: resultTargets,
+	}, nil
+}
+
+// signature returns the function type of the method, with named params and
+// results kept as they are in the source.
+func (m *method) signature() *ast.FuncType {
+	return &ast.FuncType{
+		Params:  &ast.FieldList{List: m.params},
+		Results: &ast.FieldList{List: m.results},
+	}
+}
+
+// unnamedSignature returns the function type of the method without result
+// names, so bodies are free to declare locals without clashing with them.
+func (m *method) unnamedSignature() *ast.FuncType {
+	results := make([]*ast.Field, 0, len(m.results))
+
+	for _, typ := range m.resultTypes() {
+		results = append(results, &ast.Field{Type: typ})
+	}
+
+	return &ast.FuncType{
+		Params:  &ast.FieldList{List: m.params},
+		Results: &ast.FieldList{List: results},
+	}
+}
+
+func (m *method) resultTypes() []ast.Expr {
+	types := make([]ast.Expr, 0, len(m.results))
+
+	for _, r := range m.results {
+		for range max(len(r.Names), 1) {
+			types = append(types, r.Type)
+		}
+	}
+
+	return types
+}
+
+func (m *method) paramNames() []string {
+	names := make([]string, 0, len(m.params))
+
+	for _, p := range m.params {
+		for _, id := range p.Names {
+			names = append(names, id.Name)
+		}
+	}
+
+	return names
+}
+
+// paramTypes returns the type of every param, with the variadic one turned
+// into the slice it is received as.
+func (m *method) paramTypes() []ast.Expr {
+	types := make([]ast.Expr, 0, len(m.params))
+
+	for _, p := range m.params {
+		typ := p.Type
+
+		if e, ok := typ.(*ast.Ellipsis); ok {
+			typ = &ast.ArrayType{Elt: e.Elt}
+		}
+
+		for range p.Names {
+			types = append(types, typ)
+		}
+	}
+
+	return types
+}
+
+// call calls fn with every param of the method, spreading the variadic one.
+func (m *method) call(fn ast.Expr) *ast.CallExpr {
+	names := m.paramNames()
+	args := make([]ast.Expr, len(names))
+
+	for i, n := range names {
+		args[i] = ast.NewIdent(n)
+	}
+
+	call := &ast.CallExpr{
+		Fun:  fn,
+		Args: args,
+	}
+
+	if m.variadic {
+		call.Ellipsis = 1
+	}
+
+	return call
+}
+
+// varargs collects every param into a []any named varargs, spreading the
+// variadic one, and returns the statements doing so.
+func varargs(m *method, elems func(last ast.Expr) []ast.Stmt) []ast.Stmt {
+	names := m.paramNames()
+
+	fixed := make([]ast.Expr, 0, len(names)-1)
+
+	for _, n := range names[:len(names)-1] {
+		fixed = append(fixed, ast.NewIdent(n))
+	}
+
+	stmts := []ast.Stmt{
+		assign(token.DEFINE, idents("varargs"), &ast.CompositeLit{
+			Type: &ast.ArrayType{Elt: ast.NewIdent("any")},
+			Elts: fixed,
+		}),
+	}
+
+	return append(stmts, elems(ast.NewIdent(names[len(names)-1]))...)
+}
+
+// spreadArgs collects every param into a []any named varargs, appending the
+// elements of the variadic one one by one.
+func (m *method) spreadArgs() []ast.Stmt {
+	return varargs(m, func(last ast.Expr) []ast.Stmt {
+		return []ast.Stmt{
+			&ast.RangeStmt{
+				Key:   ast.NewIdent("_"),
+				Value: ast.NewIdent("a"),
+				Tok:   token.DEFINE,
+				X:     last,
+				Body: &ast.BlockStmt{List: []ast.Stmt{
+					assign(token.ASSIGN, idents("varargs"), call(ast.NewIdent("append"), ast.NewIdent("varargs"), ast.NewIdent("a"))),
+				}},
+			},
+		}
+	})
+}
+
+// recordFields returns one exported struct field per param, used to record
+// the arguments of a call.
+func (m *method) recordFields() []*ast.Field {
+	names := m.paramNames()
+	types := m.paramTypes()
+	taken := make(map[string]bool, len(names))
+
+	fields := make([]*ast.Field, len(names))
+
+	for i, n := range names {
+		fields[i] = &ast.Field{
+			Names: []*ast.Ident{ast.NewIdent(unique(upperFirst(n), taken))},
+			Type:  types[i],
+		}
+	}
+
+	return fields
+}
+
+func unique(name string, taken map[string]bool) string {
+	candidate := name
+
+	for counter := 1; taken[candidate]; counter++ {
+		candidate = fmt.Sprintf("%s%d", name, counter)
+	}
+
+	taken[candidate] = true
+
+	return candidate
+}
+
+func upperFirst(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+
+	return string(unicode.ToUpper(r)) + s[size:]
+}
+
+func sel(x ast.Expr, names ...string) ast.Expr {
+	for _, n := range names {
+		x = &ast.SelectorExpr{
+			X:   x,
+			Sel: ast.NewIdent(n),
+		}
+	}
+
+	return x
+}
+
+func qualified(qual types.Qualifier, pkg *types.Package, name string) ast.Expr {
+	if alias := qual(pkg); alias != "" {
+		return sel(ast.NewIdent(alias), name)
+	}
+
+	return ast.NewIdent(name)
+}
+
+func call(fn ast.Expr, args ...ast.Expr) *ast.CallExpr {
+	return &ast.CallExpr{
+		Fun:  fn,
+		Args: args,
+	}
+}
+
+func str(s string) *ast.BasicLit {
+	return &ast.BasicLit{
+		Kind:  token.STRING,
+		Value: fmt.Sprintf("%q", s),
+	}
+}
+
+func exprStmt(x ast.Expr) ast.Stmt {
+	return &ast.ExprStmt{X: x}
+}
+
+func assign(tok token.Token, lhs []ast.Expr, rhs ...ast.Expr) ast.Stmt {
+	return &ast.AssignStmt{
+		Lhs: lhs,
+		Tok: tok,
+		Rhs: rhs,
+	}
+}
+
+func idents(names ...string) []ast.Expr {
+	exprs := make([]ast.Expr, len(names))
+
+	for i, n := range names {
+		exprs[i] = ast.NewIdent(n)
+	}
+
+	return exprs
+}
+
+// typeParamNames returns the names declared by a type param list, to
+// instantiate generated generic types with their own params.
+func typeParamNames(tp *ast.FieldList) []ast.Expr {
+	if tp == nil {
+		return nil
+	}
+
+	names := make([]ast.Expr, 0, len(tp.List))
+
+	for _, f := range tp.List {
+		for _, n := range f.Names {
+			names = append(names, ast.NewIdent(n.Name))
+		}
+	}
+
+	return names
+}
+
+// funcDecl declares a method named name on a pointer receiver recv of the
+// type typeName instantiated with its type params.
+func funcDecl(recv, typeName string, tp *ast.FieldList, name string, typ *ast.FuncType, body ...ast.Stmt) *ast.FuncDecl {
+	return &ast.FuncDecl{
+		Recv: &ast.FieldList{
+			List: []*ast.Field{{
+				Names: []*ast.Ident{ast.NewIdent(recv)},
+				Type: &ast.StarExpr{
+					X: typeast.Index(ast.NewIdent(typeName), typeParamNames(tp)),
+				},
+			}},
+		},
+		Name: ast.NewIdent(name),
+		Type: typ,
+		Body: &ast.BlockStmt{List: body},
+	}
+}
+
+// structType builds a struct type, keeping empty ones on a single line.
+func structType(fields []*ast.Field) *ast.StructType {
+	fl := &ast.FieldList{List: fields}
+
+	if len(fields) == 0 {
+		fl.Opening, fl.Closing = 1, 1
+	}
+
+	return &ast.StructType{Fields: fl}
+}
+
+func typeDecl(name string, tp *ast.FieldList, typ ast.Expr) *ast.GenDecl {
+	return &ast.GenDecl{
+		Tok: token.TYPE,
+		Specs: []ast.Spec{
+			&ast.TypeSpec{
+				Name:       ast.NewIdent(name),
+				TypeParams: tp,
+				Type:       typ,
+			},
+		},
+	}
+}
+
+// implementsDecl asserts at compile time that a pointer to the named type
+// implements iface.
+func implementsDecl(iface, typeName string) *ast.GenDecl {
+	return &ast.GenDecl{
+		Tok: token.VAR,
+		Specs: []ast.Spec{
+			&ast.ValueSpec{
+				Names: []*ast.Ident{ast.NewIdent("_")},
+				Type:  ast.NewIdent(iface),
+				Values: []ast.Expr{
+					&ast.UnaryExpr{
+						Op: token.AND,
+						X:  &ast.CompositeLit{Type: ast.NewIdent(typeName)},
+					},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,186 @@
+package astfile
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"github.com/nuvrel/moldable/internal/typeast"
+)
+
+var syncPackage = types.NewPackage("sync", "sync")
+
+// buildMoqs emits, for every interface, a moq style mock: a struct with one
+// function field per method, which the method calls after recording its
+// arguments, and a Calls accessor per method to inspect them.
+func (f *File) buildMoqs(qual types.Qualifier) ([]ast.Decl, error) {
+	decls := make([]ast.Decl, 0)
+
+	for _, is := range f.interfaces {
+		d, err := f.buildMoq(is, qual)
+		if err != nil {
+			return nil, fmt.Errorf("building mock for %q: %w", is.Name, err)
+		}
+
+		decls = append(decls, d...)
+	}
+
+	return decls, nil
+}
+
+func (f *File) buildMoq(is *InterfaceSpec, qual types.Qualifier) ([]ast.Decl, error) {
+	name := is.Name + "Mock"
+
+	tp, err := typeast.ConvertTypeParams(is.TypeParams, qual)
+	if err != nil {
+		return nil, fmt.Errorf("converting type params: %w", err)
+	}
+
+	methods := make([]*method, 0, len(is.Methods))
+
+	for _, m := range is.Methods {
+		mm, err := f.method(m, qual, "mock", "callInfo")
+		if err != nil {
+			return nil, err
+		}
+
+		methods = append(methods, mm)
+	}
+
+	funcs := make([]*ast.Field, 0, len(methods))
+	calls := make([]*ast.Field, 0, len(methods))
+	locks := make([]*ast.Field, 0, len(methods))
+
+	for _, m := range methods {
+		funcs = append(funcs, &ast.Field{
+			Names: []*ast.Ident{ast.NewIdent(m.name + "Func")},
+			Type:  m.signature(),
+		})
+
+		calls = append(calls, &ast.Field{
+			Names: []*ast.Ident{ast.NewIdent(m.name)},
+			Type:  &ast.ArrayType{Elt: recordType(m)},
+		})
+
+		locks = append(locks, &ast.Field{
+			Names: []*ast.Ident{ast.NewIdent("lock" + m.name)},
+			Type:  qualified(qual, syncPackage, "RWMutex"),
+		})
+	}
+
+	fields := make([]*ast.Field, 0, len(funcs)+len(locks)+1)
+
+	fields = append(fields, funcs...)
+	fields = append(fields, &ast.Field{
+		Names: []*ast.Ident{ast.NewIdent("calls")},
+		Type:  structType(calls),
+	})
+	fields = append(fields, locks...)
+
+	decls := []ast.Decl{
+		typeDecl(name, tp, structType(fields)),
+	}
+
+	if is.TypeParams == nil {
+		decls = append(decls, implementsDecl(is.Name, name))
+	}
+
+	for _, m := range methods {
+		decls = append(decls, moqMethod(is, name, tp, m), moqCalls(name, tp, m))
+	}
+
+	return decls, nil
+}
+
+func recordType(m *method) ast.Expr {
+	return structType(m.recordFields())
+}
+
+// moqMethod implements the interface method on the mock:
+//
+//	if mock.MFunc == nil { panic(...) }
+//	callInfo := struct{...}{...}
+//	mock.lockM.Lock()
+//	mock.calls.M = append(mock.calls.M, callInfo)
+//	mock.lockM.Unlock()
+//	return mock.MFunc(...)
+func moqMethod(is *InterfaceSpec, name string, tp *ast.FieldList, m *method) ast.Decl {
+	mock := ast.NewIdent("mock")
+	fn := sel(mock, m.name+"Func")
+	lock := sel(mock, "lock"+m.name)
+	record := sel(mock, "calls", m.name)
+
+	fields := m.recordFields()
+	names := m.paramNames()
+	elts := make([]ast.Expr, len(fields))
+
+	for i, f := range fields {
+		elts[i] = &ast.KeyValueExpr{
+			Key:   ast.NewIdent(f.Names[0].Name),
+			Value: ast.NewIdent(names[i]),
+		}
+	}
+
+	panicMsg := fmt.Sprintf("%s.%sFunc: method is nil but %s.%s was just called", name, m.name, is.Name, m.name)
+
+	body := []ast.Stmt{
+		&ast.IfStmt{
+			Cond: &ast.BinaryExpr{X: fn, Op: token.EQL, Y: ast.NewIdent("nil")},
+			Body: &ast.BlockStmt{
+				List: []ast.Stmt{exprStmt(call(ast.NewIdent("panic"), str(panicMsg)))},
+			},
+		},
+		assign(token.DEFINE, idents("callInfo"), &ast.CompositeLit{
+			Type: recordType(m),
+			Elts: elts,
+		}),
+		exprStmt(call(sel(lock, "Lock"))),
+		assign(token.ASSIGN, []ast.Expr{record}, call(ast.NewIdent("append"), record, ast.NewIdent("callInfo"))),
+		exprStmt(call(sel(lock, "Unlock"))),
+	}
+
+	if len(m.results) == 0 {
+		body = append(body, exprStmt(m.call(fn)))
+	} else {
+		body = append(body, &ast.ReturnStmt{Results: []ast.Expr{m.call(fn)}})
+	}
+
+	return funcDecl("mock", name, tp, m.name, m.signature(), body...)
+}
+
+// moqCalls returns the recorded calls of a method:
+//
+//	var calls []struct{...}
+//	mock.lockM.RLock()
+//	calls = mock.calls.M
+//	mock.lockM.RUnlock()
+//	return calls
+func moqCalls(name string, tp *ast.FieldList, m *method) ast.Decl {
+	mock := ast.NewIdent("mock")
+	lock := sel(mock, "lock"+m.name)
+	typ := &ast.ArrayType{Elt: recordType(m)}
+
+	body := []ast.Stmt{
+		&ast.DeclStmt{
+			Decl: &ast.GenDecl{
+				Tok: token.VAR,
+				Specs: []ast.Spec{
+					&ast.ValueSpec{
+						Names: []*ast.Ident{ast.NewIdent("calls")},
+						Type:  typ,
+					},
+				},
+			},
+		},
+		exprStmt(call(sel(lock, "RLock"))),
+		assign(token.ASSIGN, idents("calls"), sel(mock, "calls", m.name)),
+		exprStmt(call(sel(lock, "RUnlock"))),
+		&ast.ReturnStmt{Results: idents("calls")},
+	}
+
+	return funcDecl("mock", name, tp, m.name+"Calls", &ast.FuncType{
+		Params:  &ast.FieldList{},
+		Results: &ast.FieldList{List: []*ast.Field{{Type: typ}}},
+	}, body...)
+}
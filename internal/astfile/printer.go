@@ -0,0 +1,49 @@
+package astfile
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"io"
+)
+
+// Printer renders files and prints them to out, each one preceded by a header
+// with the path it would have been written to.
+type Printer struct {
+	out io.Writer
+}
+
+var (
+	_ Writer = (*Printer)(nil)
+)
+
+func NewPrinter(out io.Writer) *Printer {
+	return &Printer{
+		out: out,
+	}
+}
+
+func (p Printer) Write(fset *token.FileSet, file *ast.File, path string) (bool, error) {
+	formatted, err := Render(fset, file, path)
+	if err != nil {
+		return false, fmt.Errorf("rendering file: %w", err)
+	}
+
+	if _, err := fmt.Fprintf(p.out, "// ==> %s <==\n%s\n", path, formatted); err != nil {
+		return false, fmt.Errorf("printing file: %w", err)
+	}
+
+	return true, nil
+}
+
+func (p Printer) Remove(path string) error {
+	if _, err := fmt.Fprintf(p.out, "// ==> %s (removed) <==\n\n", path); err != nil {
+		return fmt.Errorf("printing removal: %w", err)
+	}
+
+	return nil
+}
+
+func (p Printer) Persists() bool {
+	return false
+}
@@ -0,0 +1,85 @@
+package astfile
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+)
+
+func (f *File) qualifiers() map[string]bool {
+	names := make(map[string]bool, len(f.imports))
+
+	for _, is := range f.imports {
+		names[is.Alias] = true
+	}
+
+	return names
+}
+
+// renameParams renames parameters and results of ft that would shadow an
+// import qualifier, a type referenced by the signature or a keyword, using the
+// same counter scheme as import aliases so the output stays deterministic.
+func renameParams(ft *ast.FuncType, reserved map[string]bool) {
+	fields := make([]*ast.Field, 0)
+
+	for _, fl := range []*ast.FieldList{ft.Params, ft.Results} {
+		if fl != nil {
+			fields = append(fields, fl.List...)
+		}
+	}
+
+	used := make(map[string]bool)
+	taken := make(map[string]bool)
+
+	for _, field := range fields {
+		collectTypeNames(field.Type, used)
+
+		for _, id := range field.Names {
+			taken[id.Name] = true
+		}
+	}
+
+	conflicts := func(name string) bool {
+		return reserved[name] || used[name] || token.IsKeyword(name)
+	}
+
+	for _, field := range fields {
+		for _, id := range field.Names {
+			if id.Name == "" || id.Name == "_" || !conflicts(id.Name) {
+				continue
+			}
+
+			counter := 1
+
+			for {
+				name := fmt.Sprintf("%s%d", id.Name, counter)
+
+				if !taken[name] && !conflicts(name) {
+					id.Name = name
+					taken[name] = true
+
+					break
+				}
+
+				counter++
+			}
+		}
+	}
+}
+
+func collectTypeNames(expr ast.Expr, names map[string]bool) {
+	ast.Inspect(expr, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.SelectorExpr:
+			if id, ok := n.X.(*ast.Ident); ok {
+				names[id.Name] = true
+			}
+
+			return false
+		case *ast.Ident:
+			names[n.Name] = true
+		}
+
+		return true
+	})
+}
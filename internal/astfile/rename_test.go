@@ -0,0 +1,217 @@
+package astfile_test
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+	"testing"
+
+	"github.com/nuvrel/moldable/internal/astfile"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// renameSource returns a package declaring the struct T and the types that
+// "name type" pairs may use. go/types is used directly rather than parsing
+// source so that keywords can be used as param names.
+func renameSource() (*types.Package, map[string]types.Type) {
+	pkg := types.NewPackage("example.com/p", "p")
+	ctx := types.NewPackage("context", "context")
+
+	st := types.NewNamed(types.NewTypeName(token.NoPos, pkg, "T", nil), types.NewStruct(nil, nil), nil)
+	context := types.NewNamed(types.NewTypeName(token.NoPos, ctx, "Context", nil), types.NewInterfaceType(nil, nil).Complete(), nil)
+
+	pkg.Scope().Insert(st.Obj())
+
+	return pkg, map[string]types.Type{
+		"int":             types.Typ[types.Int],
+		"string":          types.Typ[types.String],
+		"bool":            types.Typ[types.Bool],
+		"error":           types.Universe.Lookup("error").Type(),
+		"T":               st,
+		"[]T":             types.NewSlice(st),
+		"func()":          types.NewSignatureType(nil, nil, nil, nil, nil, false),
+		"context.Context": context,
+	}
+}
+
+// tuple builds a tuple from "name type" pairs; entries without a name become
+// unnamed variables.
+func tuple(t *testing.T, pkg *types.Package, known map[string]types.Type, list []string) *types.Tuple {
+	t.Helper()
+
+	vars := make([]*types.Var, 0, len(list))
+
+	for _, entry := range list {
+		name, typ, named := strings.Cut(entry, " ")
+		if !named {
+			name, typ = "", entry
+		}
+
+		vt, ok := known[typ]
+		require.True(t, ok, "unknown type %q", typ)
+
+		vars = append(vars, types.NewParam(token.NoPos, pkg, name, vt))
+	}
+
+	return types.NewTuple(vars...)
+}
+
+// names returns the names of the params and results of the method M of the
+// interface or type named owner in file, leaving out unnamed ones.
+func names(t *testing.T, file *ast.File, owner string) []string {
+	t.Helper()
+
+	var ft *ast.FuncType
+
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				ts, ok := spec.(*ast.TypeSpec)
+				if !ok || ts.Name.Name != owner {
+					continue
+				}
+
+				if it, ok := ts.Type.(*ast.InterfaceType); ok {
+					ft = it.Methods.List[0].Type.(*ast.FuncType)
+				}
+			}
+		case *ast.FuncDecl:
+			if d.Recv == nil || d.Name.Name != "M" {
+				continue
+			}
+
+			if star, ok := d.Recv.List[0].Type.(*ast.StarExpr); ok && star.X.(*ast.Ident).Name == owner {
+				ft = d.Type
+			}
+		}
+	}
+
+	require.NotNil(t, ft, "method M of %s not found", owner)
+
+	got := make([]string, 0)
+
+	for _, fl := range []*ast.FieldList{ft.Params, ft.Results} {
+		if fl == nil {
+			continue
+		}
+
+		for _, field := range fl.List {
+			for _, id := range field.Names {
+				if id.Name != "" {
+					got = append(got, id.Name)
+				}
+			}
+		}
+	}
+
+	return got
+}
+
+func TestRenameParams(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name     string
+		params   []string
+		results  []string
+		imports  []string
+		want     []string
+		delegate []string
+	}{
+		{
+			name:    "no conflicts",
+			params:  []string{"a int", "b string"},
+			results: []string{"err error"},
+			want:    []string{"a", "b", "err"},
+		},
+		{
+			name:    "import qualifier",
+			params:  []string{"ctx context.Context", "io int"},
+			imports: []string{"io"},
+			want:    []string{"ctx", "io1"},
+		},
+		{
+			name:   "qualifier used by the signature",
+			params: []string{"context context.Context"},
+			want:   []string{"context1"},
+		},
+		{
+			name:    "type used by the signature",
+			params:  []string{"string string", "p []T"},
+			results: []string{"error error"},
+			want:    []string{"string1", "p1", "error1"},
+		},
+		{
+			name:   "keyword",
+			params: []string{"type string", "func func()"},
+			want:   []string{"type1", "func1"},
+		},
+		{
+			name:     "generated locals",
+			params:   []string{"d int", "v string"},
+			results:  []string{"ok bool"},
+			want:     []string{"d", "v", "ok"},
+			delegate: []string{"d1", "v1", "ok1"},
+		},
+		{
+			name:    "counter skips taken names",
+			params:  []string{"m int", "m1 int"},
+			results: []string{"m2 error"},
+			imports: []string{"m"},
+			want:    []string{"m3", "m1", "m2"},
+		},
+		{
+			name:    "counter skips conflicting names",
+			params:  []string{"a int"},
+			imports: []string{"a", "a1"},
+			want:    []string{"a2"},
+		},
+		{
+			name:    "every conflicting name",
+			params:  []string{"d int"},
+			results: []string{"d1 int"},
+			imports: []string{"d", "d1"},
+			want:    []string{"d2", "d11"},
+		},
+		{
+			name:    "blank and unnamed",
+			params:  []string{"_ string"},
+			results: []string{"string"},
+			want:    []string{"_"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			pkg, known := renameSource()
+
+			sig := types.NewSignatureType(nil, nil, nil, tuple(t, pkg, known, c.params), tuple(t, pkg, known, c.results), false)
+
+			f := astfile.New(token.NewFileSet(), "contract", astfile.Options{Delegate: c.delegate != nil})
+
+			for _, alias := range c.imports {
+				f.AddImport(&astfile.ImportSpec{Path: "example.com/" + alias, Alias: alias})
+			}
+
+			f.AddInterface(&astfile.InterfaceSpec{
+				Name:    "TContract",
+				Source:  pkg.Scope().Lookup("T").(*types.TypeName),
+				Methods: []*types.Func{types.NewFunc(token.NoPos, pkg, "M", sig)},
+			})
+
+			file, err := f.Build(func(p *types.Package) string { return p.Name() })
+			require.NoError(t, err)
+
+			assert.Equal(t, c.want, names(t, file, "TContract"))
+
+			if c.delegate != nil {
+				assert.Equal(t, c.delegate, names(t, file, "TContractDelegate"))
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package astfile
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"github.com/nuvrel/moldable/internal/typeast"
+)
+
+// stubKind tells what the methods of a stub do.
+type stubKind int
+
+const (
+	// noop methods return zero values.
+	noop stubKind = iota
+	// unimplemented methods panic, or return the configured error if their
+	// last result is one.
+	unimplemented
+)
+
+func (k stubKind) prefix() string {
+	if k == unimplemented {
+		return "Unimplemented"
+	}
+
+	return "Noop"
+}
+
+// buildStubs emits, for every interface, a base implementation named after
+// it, meant to be embedded by partial fakes that override only
+// the methods they need.
+func (f *File) buildStubs(kind stubKind, qual types.Qualifier) ([]ast.Decl, error) {
+	decls := make([]ast.Decl, 0)
+
+	for _, is := range f.interfaces {
+		d, err := f.buildStub(is, kind, qual)
+		if err != nil {
+			return nil, fmt.Errorf("building %s stub for %q: %w", kind.prefix(), is.Name, err)
+		}
+
+		decls = append(decls, d...)
+	}
+
+	return decls, nil
+}
+
+func (f *File) buildStub(is *InterfaceSpec, kind stubKind, qual types.Qualifier) ([]ast.Decl, error) {
+	name := kind.prefix() + is.Name
+
+	tp, err := typeast.ConvertTypeParams(is.TypeParams, qual)
+	if err != nil {
+		return nil, fmt.Errorf("converting type params: %w", err)
+	}
+
+	fields := make([]*ast.Field, 0, 1)
+
+	if kind == unimplemented {
+		fields = append(fields, &ast.Field{
+			Names: []*ast.Ident{ast.NewIdent("Err")},
+			Type:  ast.NewIdent("error"),
+		})
+	}
+
+	decls := []ast.Decl{
+		typeDecl(name, tp, structType(fields)),
+	}
+
+	if is.TypeParams == nil {
+		decls = append(decls, implementsDecl(is.Name, name))
+	}
+
+	for _, fn := range is.Methods {
+		sig, ok := fn.Type().(*types.Signature)
+		if !ok {
+			return nil, fmt.Errorf("method %q is not a function", fn.Name())
+		}
+
+		locals := []string{"s"}
+
+		for i := range sig.Results().Len() {
+			locals = append(locals, fmt.Sprintf("r%d", i))
+		}
+
+		m, err := f.method(fn, qual, locals...)
+		if err != nil {
+			return nil, err
+		}
+
+		body, rets := zeroResults(m)
+
+		if kind == unimplemented {
+			panicStmt := exprStmt(call(ast.NewIdent("panic"), str(fmt.Sprintf("%s.%s is not implemented", is.baseName(), m.name))))
+
+			if returnsError(sig) {
+				errField := sel(ast.NewIdent("s"), "Err")
+
+				body = append([]ast.Stmt{&ast.IfStmt{
+					Cond: &ast.BinaryExpr{X: errField, Op: token.EQL, Y: ast.NewIdent("nil")},
+					Body: &ast.BlockStmt{List: []ast.Stmt{panicStmt}},
+				}}, body[:len(body)-1]...)
+
+				rets[len(rets)-1] = errField
+			} else {
+				body, rets = []ast.Stmt{panicStmt}, nil
+			}
+		}
+
+		if len(rets) > 0 {
+			body = append(body, &ast.ReturnStmt{Results: rets})
+		}
+
+		decls = append(decls, funcDecl("s", name, tp, m.name, m.unnamedSignature(), body...))
+	}
+
+	return decls, nil
+}
+
+// zeroResults declares a zero valued variable per result of the method and
+// returns the declarations along with the variables.
+func zeroResults(m *method) ([]ast.Stmt, []ast.Expr) {
+	results := m.resultTypes()
+
+	stmts := make([]ast.Stmt, len(results))
+	rets := make([]ast.Expr, len(results))
+
+	for i, typ := range results {
+		r := ast.NewIdent(fmt.Sprintf("r%d", i))
+
+		stmts[i] = &ast.DeclStmt{Decl: &ast.GenDecl{
+			Tok:   token.VAR,
+			Specs: []ast.Spec{&ast.ValueSpec{Names: []*ast.Ident{r}, Type: typ}},
+		}}
+		rets[i] = r
+	}
+
+	return stmts, rets
+}
@@ -0,0 +1,64 @@
+// Package fixture declares the types the emitters are tested against. Their
+// methods cover variadic, unnamed and named params and results, params named
+// like imports or like the locals of generated bodies, generic and value
+// receivers, and pointers to other sources for deep mode.
+package fixture
+
+import (
+	"context"
+	"io"
+)
+
+type Item struct {
+	ID int
+}
+
+type Store struct{}
+
+func (s *Store) Get(ctx context.Context, id int) (*Item, error) { return nil, nil }
+
+func (s *Store) Put(context.Context, *Item) error { return nil }
+
+func (s *Store) Tags(prefix string, tags ...string) []string { return nil }
+
+func (s *Store) Copy(w io.Writer, r io.Reader) (n int64, err error) { return 0, nil }
+
+func (s *Store) Close() {}
+
+func (s *Store) Shadow(context context.Context, fixture string, string int) error { return nil }
+
+func (s *Store) Locals(mock, callInfo, m, mr, ret, varargs, a, _m, v, ok, d, inst, args, start, in0, r0, ret0 int) bool {
+	return false
+}
+
+func (s *Store) Child(name string) *Store { return nil }
+
+func (s *Store) Merge(other *Store, rest ...*Store) {}
+
+type Box[T any] struct{}
+
+func (b *Box[T]) Get() T { return *new(T) }
+
+func (b *Box[T]) Set(v T, more ...T) {}
+
+func (b *Box[T]) Map(fn func(T) T) *Box[T] { return b }
+
+type Point struct {
+	X, Y int
+}
+
+func (p Point) Add(q Point) Point { return Point{X: p.X + q.X, Y: p.Y + q.Y} }
+
+func (p Point) String() string { return "" }
+
+type Handler func(string) error
+
+func (h Handler) Serve(path string) error { return h(path) }
+
+func Open(name string) (*Store, error) { return nil, nil }
+
+func Join(sep string, parts ...string) string { return "" }
+
+func Reset() {}
+
+func Swap(d, in0 *Store, r0 string) (*Store, error) { return nil, nil }
@@ -0,0 +1,195 @@
+package astfile
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"github.com/nuvrel/moldable/internal/typeast"
+)
+
+var testifyPackage = types.NewPackage("github.com/stretchr/testify/mock", "mock")
+
+// buildTestifyMocks emits, for every interface, a struct embedding
+// testify's mock.Mock whose methods report their calls to it and extract
+// typed results from the configured returns.
+func (f *File) buildTestifyMocks(qual types.Qualifier) ([]ast.Decl, error) {
+	decls := make([]ast.Decl, 0)
+
+	for _, is := range f.interfaces {
+		d, err := f.buildTestifyMock(is, qual)
+		if err != nil {
+			return nil, fmt.Errorf("building testify mock for %q: %w", is.Name, err)
+		}
+
+		decls = append(decls, d...)
+	}
+
+	return decls, nil
+}
+
+func (f *File) buildTestifyMock(is *InterfaceSpec, qual types.Qualifier) ([]ast.Decl, error) {
+	name := is.Name + "TestifyMock"
+
+	tp, err := typeast.ConvertTypeParams(is.TypeParams, qual)
+	if err != nil {
+		return nil, fmt.Errorf("converting type params: %w", err)
+	}
+
+	mockType := &ast.StarExpr{X: typeast.Index(ast.NewIdent(name), typeParamNames(tp))}
+
+	// t is declared as interface{ mock.TestingT; Cleanup(func()) }, which
+	// both *testing.T and *testing.B satisfy.
+	testingT := &ast.InterfaceType{
+		Methods: &ast.FieldList{List: []*ast.Field{
+			{Type: qualified(qual, testifyPackage, "TestingT")},
+			{
+				Names: []*ast.Ident{ast.NewIdent("Cleanup")},
+				Type: &ast.FuncType{
+					Params: &ast.FieldList{List: []*ast.Field{
+						{Type: &ast.FuncType{Params: &ast.FieldList{}}},
+					}},
+				},
+			},
+		}},
+	}
+
+	decls := []ast.Decl{
+		typeDecl(name, tp, structType([]*ast.Field{
+			{Type: qualified(qual, testifyPackage, "Mock")},
+		})),
+		&ast.FuncDecl{
+			Name: ast.NewIdent("New" + name),
+			Type: &ast.FuncType{
+				TypeParams: tp,
+				Params: &ast.FieldList{List: []*ast.Field{
+					{Names: []*ast.Ident{ast.NewIdent("t")}, Type: testingT},
+				}},
+				Results: &ast.FieldList{List: []*ast.Field{{Type: mockType}}},
+			},
+			Body: &ast.BlockStmt{List: []ast.Stmt{
+				assign(token.DEFINE, idents("m"), &ast.UnaryExpr{
+					Op: token.AND,
+					X:  &ast.CompositeLit{Type: typeast.Index(ast.NewIdent(name), typeParamNames(tp))},
+				}),
+				exprStmt(call(sel(ast.NewIdent("m"), "Mock", "Test"), ast.NewIdent("t"))),
+				exprStmt(call(sel(ast.NewIdent("t"), "Cleanup"), &ast.FuncLit{
+					Type: &ast.FuncType{Params: &ast.FieldList{}},
+					Body: &ast.BlockStmt{List: []ast.Stmt{
+						exprStmt(call(sel(ast.NewIdent("m"), "AssertExpectations"), ast.NewIdent("t"))),
+					}},
+				})),
+				&ast.ReturnStmt{Results: idents("m")},
+			}},
+		},
+	}
+
+	if is.TypeParams == nil {
+		decls = append(decls, implementsDecl(is.Name, name))
+	}
+
+	for _, fn := range is.Methods {
+		locals := []string{"_m", "ret", "varargs", "a", "v"}
+
+		if sig, ok := fn.Type().(*types.Signature); ok {
+			for i := range sig.Results().Len() {
+				locals = append(locals, fmt.Sprintf("r%d", i))
+			}
+		}
+
+		m, err := f.method(fn, qual, locals...)
+		if err != nil {
+			return nil, err
+		}
+
+		decls = append(decls, testifyMethod(name, tp, m))
+	}
+
+	return decls, nil
+}
+
+// testifyMethod reports the call to the embedded mock.Mock and extracts each
+// result from the returns configured with On(...).Return(...). A result may
+// be given either as a value of the result type or as a function taking the
+// method params and computing it:
+//
+//	ret := _m.Called(args...)
+//	var r0 T
+//	switch v := ret.Get(0).(type) {
+//	case func(params) T:
+//		r0 = v(args...)
+//	case T:
+//		r0 = v
+//	}
+//	return r0
+func testifyMethod(name string, tp *ast.FieldList, m *method) ast.Decl {
+	recv := ast.NewIdent("_m")
+
+	body := make([]ast.Stmt, 0)
+	called := call(sel(recv, "Called"))
+
+	if m.variadic {
+		body = append(body, m.spreadArgs()...)
+
+		called.Args = idents("varargs")
+		called.Ellipsis = 1
+	} else {
+		called.Args = idents(m.paramNames()...)
+	}
+
+	results := m.resultTypes()
+
+	if len(results) == 0 {
+		body = append(body, exprStmt(called))
+
+		return funcDecl("_m", name, tp, m.name, m.unnamedSignature(), body...)
+	}
+
+	body = append(body, assign(token.DEFINE, idents("ret"), called))
+
+	params := make([]*ast.Field, 0, len(m.params))
+
+	for _, p := range m.params {
+		for range p.Names {
+			params = append(params, &ast.Field{Type: p.Type})
+		}
+	}
+
+	rets := make([]ast.Expr, len(results))
+
+	for i, typ := range results {
+		r := ast.NewIdent(fmt.Sprintf("r%d", i))
+
+		body = append(body,
+			&ast.DeclStmt{Decl: &ast.GenDecl{
+				Tok:   token.VAR,
+				Specs: []ast.Spec{&ast.ValueSpec{Names: []*ast.Ident{r}, Type: typ}},
+			}},
+			&ast.TypeSwitchStmt{
+				Assign: assign(token.DEFINE, idents("v"), &ast.TypeAssertExpr{
+					X: call(sel(ast.NewIdent("ret"), "Get"), &ast.BasicLit{Kind: token.INT, Value: fmt.Sprint(i)}),
+				}),
+				Body: &ast.BlockStmt{List: []ast.Stmt{
+					&ast.CaseClause{
+						List: []ast.Expr{&ast.FuncType{
+							Params:  &ast.FieldList{List: params},
+							Results: &ast.FieldList{List: []*ast.Field{{Type: typ}}},
+						}},
+						Body: []ast.Stmt{assign(token.ASSIGN, []ast.Expr{r}, m.call(ast.NewIdent("v")))},
+					},
+					&ast.CaseClause{
+						List: []ast.Expr{typ},
+						Body: []ast.Stmt{assign(token.ASSIGN, []ast.Expr{r}, ast.NewIdent("v"))},
+					},
+				}},
+			},
+		)
+
+		rets[i] = r
+	}
+
+	body = append(body, &ast.ReturnStmt{Results: rets})
+
+	return funcDecl("_m", name, tp, m.name, m.unnamedSignature(), body...)
+}
@@ -12,31 +12,107 @@ import (
 	"golang.org/x/tools/imports"
 )
 
-type Writer struct{}
+// Writer outputs rendered files. Write reports whether the file differs from
+// what was already at path, and Persists whether files end up on disk.
+type Writer interface {
+	Write(fset *token.FileSet, file *ast.File, path string) (bool, error)
+	Remove(path string) error
+	Persists() bool
+}
+
+type Disk struct{}
 
-func NewWriter() *Writer {
-	return &Writer{}
+var (
+	_ Writer = (*Disk)(nil)
+)
+
+func NewDisk() *Disk {
+	return &Disk{}
 }
 
-func (Writer) Write(fset *token.FileSet, file *ast.File, path string) error {
+// Write leaves files with identical content untouched so their modification
+// time does not change, and otherwise replaces them atomically through a
+// temporary file in the same directory.
+func (Disk) Write(fset *token.FileSet, file *ast.File, path string) (bool, error) {
+	formatted, err := Render(fset, file, path)
+	if err != nil {
+		return false, fmt.Errorf("rendering file: %w", err)
+	}
+
+	existing, err := os.ReadFile(path)
+	if err == nil && bytes.Equal(existing, formatted) {
+		return false, nil
+	}
+
 	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
-		return fmt.Errorf("creating directory: %w", err)
+		return false, fmt.Errorf("creating directory: %w", err)
+	}
+
+	if err := writeAtomic(path, formatted); err != nil {
+		return false, fmt.Errorf("writing file to disk: %w", err)
+	}
+
+	return true, nil
+}
+
+func writeAtomic(path string, data []byte) (err error) {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("creating temporary file: %w", err)
+	}
+
+	defer func() {
+		if err != nil {
+			os.Remove(tmp.Name())
+		}
+	}()
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+
+		return fmt.Errorf("writing temporary file: %w", err)
 	}
 
+	if err := tmp.Chmod(0644); err != nil {
+		tmp.Close()
+
+		return fmt.Errorf("setting temporary file mode: %w", err)
+	}
+
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("closing temporary file: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("renaming temporary file: %w", err)
+	}
+
+	return nil
+}
+
+func (Disk) Remove(path string) error {
+	if err := os.Remove(path); err != nil {
+		return fmt.Errorf("removing file from disk: %w", err)
+	}
+
+	return nil
+}
+
+func (Disk) Persists() bool {
+	return true
+}
+
+func Render(fset *token.FileSet, file *ast.File, path string) ([]byte, error) {
 	var buf bytes.Buffer
 
 	if err := format.Node(&buf, fset, file); err != nil {
-		return fmt.Errorf("formatting node: %w", err)
+		return nil, fmt.Errorf("formatting node: %w", err)
 	}
 
 	formatted, err := imports.Process(path, buf.Bytes(), nil)
 	if err != nil {
-		return fmt.Errorf("processing imports: %w", err)
+		return nil, fmt.Errorf("processing imports: %w", err)
 	}
 
-	if err := os.WriteFile(path, formatted, 0644); err != nil {
-		return fmt.Errorf("writing file to disk: %w", err)
-	}
-
-	return nil
+	return formatted, nil
 }
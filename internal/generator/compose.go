@@ -0,0 +1,75 @@
+package generator
+
+import (
+	"go/types"
+	"slices"
+
+	"github.com/nuvrel/moldable/internal/astfile"
+)
+
+// compose makes every interface include the interfaces generated in the same
+// file from the structs its source struct embeds, instead of repeating their
+// promoted methods. An embedded interface is only included when the outer one
+// has all of its methods, that is none were shadowed or filtered out, and
+// generic embedded structs are left alone.
+func compose(specs []*astfile.InterfaceSpec) {
+	bySource := make(map[*types.TypeName]*astfile.InterfaceSpec, len(specs))
+
+	for _, spec := range specs {
+		if spec.Source != nil {
+			bySource[spec.Source] = spec
+		}
+	}
+
+	for _, spec := range specs {
+		if spec.Source == nil {
+			continue
+		}
+
+		for _, tn := range embeddedStructs(spec.Source) {
+			inner, ok := bySource[tn]
+			if !ok || inner == spec || inner.TypeParams != nil {
+				continue
+			}
+
+			all := !slices.ContainsFunc(inner.Methods, func(m *types.Func) bool {
+				return !slices.Contains(spec.Methods, m)
+			})
+
+			if all {
+				spec.Includes = append(spec.Includes, inner)
+			}
+		}
+	}
+}
+
+// embeddedStructs returns the named types embedded, directly or through a
+// pointer, in the struct tn declares.
+func embeddedStructs(tn *types.TypeName) []*types.TypeName {
+	st, ok := tn.Type().Underlying().(*types.Struct)
+	if !ok {
+		return nil
+	}
+
+	embedded := make([]*types.TypeName, 0)
+
+	for i := range st.NumFields() {
+		f := st.Field(i)
+
+		if !f.Embedded() {
+			continue
+		}
+
+		typ := types.Unalias(f.Type())
+
+		if ptr, ok := typ.(*types.Pointer); ok {
+			typ = types.Unalias(ptr.Elem())
+		}
+
+		if named, ok := typ.(*types.Named); ok {
+			embedded = append(embedded, named.Obj())
+		}
+	}
+
+	return embedded
+}
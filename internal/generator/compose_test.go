@@ -0,0 +1,94 @@
+package generator_test
+
+import (
+	"go/types"
+	"slices"
+	"testing"
+
+	"github.com/nuvrel/moldable/internal/astfile"
+	"github.com/nuvrel/moldable/internal/generator"
+	"github.com/stretchr/testify/assert"
+)
+
+const composeSource = `package p
+
+type Base struct{}
+
+func (*Base) Ping() error  { return nil }
+func (*Base) Close() error { return nil }
+
+type Client struct{ Base }
+
+func (*Client) Get() string { return "" }
+
+type Shadowed struct{ *Base }
+
+func (*Shadowed) Close() error { return nil }
+
+type Filtered struct{ Base }
+
+type Box[T any] struct{}
+
+func (*Box[T]) Get() T { var v T; return v }
+
+type Holder struct{ Box[int] }
+
+type Lone struct{ Base }
+`
+
+func TestCompose(t *testing.T) {
+	t.Parallel()
+
+	pkg := check(t, composeSource)
+
+	spec := func(name string, without ...string) *astfile.InterfaceSpec {
+		tn := pkg.Scope().Lookup(name).(*types.TypeName)
+
+		s := &astfile.InterfaceSpec{
+			Name:       name + "Contract",
+			Source:     tn,
+			TypeParams: tn.Type().(*types.Named).TypeParams(),
+		}
+
+		for _, m := range methods(pkg, name) {
+			if !slices.Contains(without, m.Name()) {
+				s.Methods = append(s.Methods, m)
+			}
+		}
+
+		return s
+	}
+
+	base := spec("Base")
+	client := spec("Client")
+	shadowed := spec("Shadowed")
+	filtered := spec("Filtered", "Close")
+	holder := spec("Holder")
+
+	// Lone embeds Base, but Base has no interface of its own in this file
+	lone := spec("Lone")
+
+	generator.Compose([]*astfile.InterfaceSpec{base, client, shadowed, filtered, spec("Box"), holder})
+	generator.Compose([]*astfile.InterfaceSpec{lone})
+
+	cases := []struct {
+		name string
+		spec *astfile.InterfaceSpec
+		want []*astfile.InterfaceSpec
+	}{
+		{name: "all inner methods present", spec: client, want: []*astfile.InterfaceSpec{base}},
+		{name: "inner method shadowed", spec: shadowed},
+		{name: "inner method filtered out", spec: filtered},
+		{name: "generic embedded struct", spec: holder},
+		{name: "embedded struct without interface", spec: lone},
+		{name: "no embedded struct", spec: base},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, c.want, c.spec.Includes)
+		})
+	}
+}
@@ -0,0 +1,236 @@
+package generator
+
+import (
+	"fmt"
+	"go/types"
+	"slices"
+
+	"github.com/nuvrel/moldable/cmd/moldable/app"
+	"github.com/nuvrel/moldable/internal/typeast"
+)
+
+// standardInterfaces are the interfaces the std entry of the embed list
+// stands for.
+var standardInterfaces = []string{
+	"error",
+	"context.Context",
+	"encoding.BinaryMarshaler",
+	"encoding.BinaryUnmarshaler",
+	"encoding.TextMarshaler",
+	"encoding.TextUnmarshaler",
+	"encoding/json.Marshaler",
+	"encoding/json.Unmarshaler",
+	"fmt.Formatter",
+	"fmt.GoStringer",
+	"fmt.Stringer",
+	"io.ByteReader",
+	"io.ByteScanner",
+	"io.ByteWriter",
+	"io.Closer",
+	"io.ReadCloser",
+	"io.ReadSeekCloser",
+	"io.ReadSeeker",
+	"io.ReadWriteCloser",
+	"io.ReadWriteSeeker",
+	"io.ReadWriter",
+	"io.Reader",
+	"io.ReaderAt",
+	"io.ReaderFrom",
+	"io.RuneReader",
+	"io.RuneScanner",
+	"io.Seeker",
+	"io.StringWriter",
+	"io.WriteCloser",
+	"io.WriteSeeker",
+	"io.Writer",
+	"io.WriterAt",
+	"io.WriterTo",
+	"net/http.Handler",
+	"sort.Interface",
+	"sync.Locker",
+}
+
+// embedRefs expands the embed list of an output, replacing the std entry
+// with the standard interfaces.
+func embedRefs(out app.Output) []string {
+	refs := make([]string, 0, len(out.Embed))
+
+	for _, ref := range out.Embed {
+		if ref == app.EmbedStandard {
+			refs = append(refs, standardInterfaces...)
+
+			continue
+		}
+
+		refs = append(refs, ref)
+	}
+
+	return refs
+}
+
+// embedPaths returns the import paths of every package the configured embed
+// lists refer to, which have to be loaded along with the source packages.
+func (g Generator) embedPaths() []string {
+	paths := make([]string, 0)
+
+	for _, p := range g.config.Packages {
+		for _, ref := range embedRefs(g.config.OutputFor(p)) {
+			path, _ := app.EmbedRef(ref)
+
+			if path != "" && !slices.Contains(paths, path) {
+				paths = append(paths, path)
+			}
+		}
+	}
+
+	return paths
+}
+
+// embedCandidates resolves the embed list of an output into interface types.
+// Bare names are looked up in the source package, then in the universe, and
+// skipped when declared in neither.
+func (g Generator) embedCandidates(out app.Output, pkg *types.Package) ([]types.Type, error) {
+	candidates := make([]types.Type, 0)
+
+	for _, ref := range embedRefs(out) {
+		path, name := app.EmbedRef(ref)
+
+		scope := pkg.Scope()
+
+		if path != "" {
+			p, err := g.loader.Package(path)
+			if err != nil {
+				return nil, fmt.Errorf("resolving %q: %w", ref, err)
+			}
+
+			scope = p.Scope()
+		}
+
+		found := scope.Lookup(name)
+		if found == nil && path == "" {
+			found = types.Universe.Lookup(name)
+		}
+
+		if found == nil {
+			if path == "" {
+				// the list is shared by every package of the output, most
+				// of which only declare some of the bare names
+				continue
+			}
+
+			return nil, fmt.Errorf("resolving %q: no such type", ref)
+		}
+
+		obj, ok := found.(*types.TypeName)
+		if !ok {
+			return nil, fmt.Errorf("resolving %q: not an interface", ref)
+		}
+
+		if _, ok := obj.Type().Underlying().(*types.Interface); !ok {
+			return nil, fmt.Errorf("resolving %q: not an interface", ref)
+		}
+
+		if named, ok := obj.Type().(*types.Named); ok && named.TypeParams() != nil {
+			return nil, fmt.Errorf("resolving %q: generic interfaces cannot be embedded", ref)
+		}
+
+		candidates = append(candidates, obj.Type())
+	}
+
+	return candidates, nil
+}
+
+// embeds picks the candidates whose whole method set is among methods, with
+// identical signatures, and which the output package can refer to. Larger
+// interfaces are tried first, and a candidate is only kept when it provides
+// a method none of the already picked ones does.
+func embeds(candidates []types.Type, methods []*types.Func, from string) []types.Type {
+	sorted := slices.Clone(candidates)
+
+	slices.SortStableFunc(sorted, func(a, b types.Type) int {
+		return methodCount(b) - methodCount(a)
+	})
+
+	covered := make(map[string]bool)
+	picked := make([]types.Type, 0)
+
+	for _, c := range sorted {
+		if typeast.Reachable(c, from) != nil || !implemented(c, methods) {
+			continue
+		}
+
+		iface := c.Underlying().(*types.Interface)
+		adds := false
+
+		for i := range iface.NumMethods() {
+			if !covered[iface.Method(i).Name()] {
+				adds = true
+			}
+		}
+
+		if !adds {
+			continue
+		}
+
+		for i := range iface.NumMethods() {
+			covered[iface.Method(i).Name()] = true
+		}
+
+		picked = append(picked, c)
+	}
+
+	return picked
+}
+
+func methodCount(typ types.Type) int {
+	return typ.Underlying().(*types.Interface).NumMethods()
+}
+
+// sameSignature compares signatures through their fully qualified type
+// strings, as the interfaces may come from a separate load of their package
+// and thus not share type objects with the source package.
+func sameSignature(a, b *types.Signature) bool {
+	if a.Variadic() != b.Variadic() {
+		return false
+	}
+
+	same := func(x, y *types.Tuple) bool {
+		if x.Len() != y.Len() {
+			return false
+		}
+
+		for i := range x.Len() {
+			if types.TypeString(x.At(i).Type(), nil) != types.TypeString(y.At(i).Type(), nil) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	return same(a.Params(), b.Params()) && same(a.Results(), b.Results())
+}
+
+// implemented reports whether every method of the interface typ has a
+// counterpart with an identical signature among methods.
+func implemented(typ types.Type, methods []*types.Func) bool {
+	iface := typ.Underlying().(*types.Interface)
+
+	if iface.NumMethods() == 0 {
+		return false
+	}
+
+	for i := range iface.NumMethods() {
+		im := iface.Method(i)
+
+		found := slices.ContainsFunc(methods, func(m *types.Func) bool {
+			return m.Id() == im.Id() && sameSignature(m.Signature(), im.Signature())
+		})
+
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,198 @@
+package generator_test
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"strings"
+	"testing"
+
+	"github.com/nuvrel/moldable/cmd/moldable/app"
+	"github.com/nuvrel/moldable/internal/generator"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const embedSource = `package p
+
+import "io"
+
+type Named interface{ Name() string }
+
+type sized interface{ Size() int }
+
+type Matcher interface{ Match(string) bool }
+
+type File struct{}
+
+func (*File) Read(p []byte) (int, error) { return 0, nil }
+func (*File) Close() error               { return nil }
+func (*File) Name() string               { return "" }
+func (*File) Size() int                  { return 0 }
+
+type Short struct{}
+
+func (*Short) Read(p []byte) (int64, error) { return 0, nil }
+func (*Short) Close() error                 { return nil }
+
+var _ io.Reader
+`
+
+// check type-checks src as the package example.com/p, its imports from source.
+func check(t *testing.T, src string) *types.Package {
+	t.Helper()
+
+	fset := token.NewFileSet()
+
+	file, err := parser.ParseFile(fset, "p.go", src, 0)
+	require.NoError(t, err)
+
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+
+	pkg, err := conf.Check("example.com/p", fset, []*ast.File{file}, nil)
+	require.NoError(t, err)
+
+	return pkg
+}
+
+// lookup returns the type named ref, either a name declared in pkg or a
+// qualified name from one of its imports.
+func lookup(t *testing.T, pkg *types.Package, ref string) types.Type {
+	t.Helper()
+
+	scope := pkg.Scope()
+
+	if path, name, ok := strings.Cut(ref, "."); ok {
+		for _, imp := range pkg.Imports() {
+			if imp.Path() == path {
+				scope = imp.Scope()
+			}
+		}
+
+		ref = name
+	}
+
+	obj := scope.Lookup(ref)
+	require.NotNil(t, obj, "looking up %s", ref)
+
+	return obj.Type()
+}
+
+func methods(pkg *types.Package, name string) []*types.Func {
+	ms := types.NewMethodSet(types.NewPointer(pkg.Scope().Lookup(name).Type()))
+	fns := make([]*types.Func, 0, ms.Len())
+
+	for i := range ms.Len() {
+		fns = append(fns, ms.At(i).Obj().(*types.Func))
+	}
+
+	return fns
+}
+
+func TestEmbeds(t *testing.T) {
+	t.Parallel()
+
+	pkg := check(t, embedSource)
+
+	cases := []struct {
+		name       string
+		source     string
+		candidates []string
+		from       string
+		want       []string
+	}{
+		{
+			name:       "larger interface over its parts",
+			source:     "File",
+			candidates: []string{"io.Reader", "io.Closer", "io.ReadCloser"},
+			from:       "example.com/contract",
+			want:       []string{"io.ReadCloser"},
+		},
+		{
+			name:       "parts of no larger interface",
+			source:     "File",
+			candidates: []string{"io.Reader", "Named"},
+			from:       "example.com/contract",
+			want:       []string{"io.Reader", "example.com/p.Named"},
+		},
+		{
+			name:       "signature mismatch",
+			source:     "Short",
+			candidates: []string{"io.ReadCloser", "io.Reader", "io.Closer"},
+			from:       "example.com/contract",
+			want:       []string{"io.Closer"},
+		},
+		{
+			name:       "method missing",
+			source:     "File",
+			candidates: []string{"Matcher", "io.Writer"},
+			from:       "example.com/contract",
+			want:       []string{},
+		},
+		{
+			name:       "unexported from another package",
+			source:     "File",
+			candidates: []string{"sized"},
+			from:       "example.com/contract",
+			want:       []string{},
+		},
+		{
+			name:       "unexported from the same package",
+			source:     "File",
+			candidates: []string{"sized"},
+			from:       "example.com/p",
+			want:       []string{"example.com/p.sized"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			candidates := make([]types.Type, 0, len(c.candidates))
+
+			for _, ref := range c.candidates {
+				candidates = append(candidates, lookup(t, pkg, ref))
+			}
+
+			got := make([]string, 0)
+
+			for _, typ := range generator.Embeds(candidates, methods(pkg, c.source), c.from) {
+				got = append(got, typ.String())
+			}
+
+			assert.Equal(t, c.want, got)
+		})
+	}
+}
+
+func TestEmbedCandidates(t *testing.T) {
+	t.Parallel()
+
+	pkg := check(t, embedSource)
+
+	t.Run("bare names", func(t *testing.T) {
+		t.Parallel()
+
+		got, err := generator.EmbedCandidates(app.Output{Embed: []string{"Named", "Undeclared", "error"}}, pkg)
+		require.NoError(t, err)
+
+		assert.Equal(t, []types.Type{lookup(t, pkg, "Named"), types.Universe.Lookup("error").Type()}, got)
+	})
+
+	t.Run("not an interface", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := generator.EmbedCandidates(app.Output{Embed: []string{"File"}}, pkg)
+		assert.ErrorContains(t, err, `resolving "File": not an interface`)
+	})
+
+	t.Run("unloaded package", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := generator.EmbedCandidates(app.Output{Embed: []string{"example.com/q.Named"}}, pkg)
+		assert.ErrorContains(t, err, `resolving "example.com/q.Named"`)
+	})
+}
@@ -0,0 +1,15 @@
+package generator
+
+import (
+	"go/types"
+
+	"github.com/nuvrel/moldable/cmd/moldable/app"
+)
+
+var Embeds = embeds
+
+func EmbedCandidates(out app.Output, pkg *types.Package) ([]types.Type, error) {
+	return New(app.Config{}, nil, nil).embedCandidates(out, pkg)
+}
+
+var Compose = compose
@@ -0,0 +1,72 @@
+package generator
+
+import (
+	"fmt"
+	"go/types"
+
+	"github.com/nuvrel/moldable/cmd/moldable/app"
+	"github.com/nuvrel/moldable/internal/astfile"
+	"github.com/nuvrel/moldable/internal/namefilter"
+	"github.com/nuvrel/moldable/internal/typeast"
+)
+
+// facade gathers the exported functions of pkg into an interface named after
+// the facade name of the package, applying the methods filter and the
+// unreachable policy as for struct methods. Nil is returned when no function
+// is left.
+func (g Generator) facade(pkg *types.Package, p app.Package, out app.Output, from string, methods *namefilter.Filter) (*astfile.InterfaceSpec, error) {
+	name := p.Facade
+	policy := g.config.UnreachablePolicy()
+	scope := pkg.Scope()
+
+	funcs := make([]*types.Func, 0)
+
+	for _, n := range scope.Names() {
+		fn, ok := scope.Lookup(n).(*types.Func)
+		if !ok || !fn.Exported() {
+			continue
+		}
+
+		if fn.Signature().TypeParams() != nil {
+			g.reporter.SkippedMethod(name, n, "generic functions cannot be interface methods")
+
+			continue
+		}
+
+		if ok, reason := methods.Match(n); !ok {
+			g.reporter.SkippedMethod(name, n, reason)
+
+			continue
+		}
+
+		if err := typeast.Reachable(fn.Type(), from); err != nil {
+			if policy == app.UnreachableFail {
+				return nil, fmt.Errorf("facade %q function %q: %w", name, n, err)
+			}
+
+			g.reporter.UnreachableMethod(name, n, err.Error())
+
+			if policy == app.UnreachableSkipStruct {
+				g.reporter.SkippedStruct(name, "unreachable types in function signatures")
+
+				return nil, nil
+			}
+
+			continue
+		}
+
+		funcs = append(funcs, fn)
+	}
+
+	if len(funcs) == 0 {
+		g.reporter.SkippedStruct(name, "no usable functions")
+
+		return nil, nil
+	}
+
+	return &astfile.InterfaceSpec{
+		Name:    out.Naming.Name(name, pkg.Name()),
+		Facade:  name,
+		Methods: funcs,
+	}, nil
+}
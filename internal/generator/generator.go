@@ -1,10 +1,12 @@
 package generator
 
 import (
+	"errors"
 	"fmt"
 	"go/token"
 	"go/types"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/nuvrel/moldable/cmd/moldable/app"
@@ -16,87 +18,295 @@ import (
 	"github.com/nuvrel/moldable/internal/typeast"
 )
 
+// outcome tells what happened to the output file of a package.
+type outcome int
+
+const (
+	skipped outcome = iota
+	unchanged
+	written
+)
+
 type Generator struct {
 	config    app.Config
 	reporter  reporter.Reporter
 	collector *structcollector.StructCollector
 	loader    *pkgload.Loader
-	writer    *astfile.Writer
+	writer    astfile.Writer
+	uses      map[pkgload.MethodUse]bool
 }
 
-func New(cfg app.Config, rep reporter.Reporter) *Generator {
+func New(cfg app.Config, rep reporter.Reporter, w astfile.Writer) *Generator {
 	return &Generator{
 		config:    cfg,
 		reporter:  rep,
 		collector: structcollector.New(),
 		loader:    pkgload.NewLoader(),
-		writer:    astfile.NewWriter(),
+		writer:    w,
 	}
 }
 
 func (g Generator) Generate() error {
 	paths := g.config.Paths()
 
+	for _, path := range g.embedPaths() {
+		if !slices.Contains(paths, path) {
+			paths = append(paths, path)
+		}
+	}
+
 	if err := g.loader.Load(paths); err != nil {
 		return fmt.Errorf("loading packages: %w", err)
 	}
 
+	if g.config.NeedsScan() {
+		uses, skipped, err := pkgload.Uses(g.config.ScanPatterns())
+		if err != nil {
+			return fmt.Errorf("scanning for method calls: %w", err)
+		}
+
+		for _, s := range skipped {
+			g.reporter.SkippedScan(s.Path, s.Reason)
+		}
+
+		g.uses = uses
+	}
+
+	outputs := make(map[string]string)
+	declared := make(map[string]map[string]string)
+	produced := make(map[string]bool)
+	counts := make(map[outcome]int)
+
 	for _, p := range g.config.Packages {
 		pkg, err := g.loader.Package(p.Path)
 		if err != nil {
 			return fmt.Errorf("using package after loading: %w", err)
 		}
 
-		if err := g.processPackage(pkg); err != nil {
+		out := g.config.OutputFor(p)
+		output := outputPath(out, pkg)
+
+		if other, ok := outputs[output]; ok {
+			return fmt.Errorf("packages %q and %q both write to %q", other, p.Path, output)
+		}
+
+		outputs[output] = p.Path
+
+		dir := filepath.Clean(out.Dir)
+
+		if declared[dir] == nil {
+			declared[dir] = make(map[string]string)
+		}
+
+		res, err := g.processPackage(pkg, p, out, declared[dir])
+		if err != nil {
 			return fmt.Errorf("processing package %q: %w", p.Path, err)
 		}
+
+		if res != skipped {
+			produced[output] = true
+		}
+
+		counts[res]++
+	}
+
+	// the printer and the checker write nothing, and report on their own
+	if g.writer.Persists() {
+		g.reporter.FilesCompleted(counts[written], counts[unchanged])
+	}
+
+	if err := g.prune(produced); err != nil {
+		return fmt.Errorf("pruning stale files: %w", err)
 	}
 
 	return nil
 }
 
-func (g Generator) processPackage(pkg *types.Package) error {
+func outputPath(out app.Output, pkg *types.Package) string {
+	filename := strings.ReplaceAll(out.Filename, "{package}", pkg.Name())
+
+	return filepath.Join(out.Dir, filename)
+}
+
+func (g Generator) filters(p app.Package) (structcollector.Filters, error) {
+	structs, err := p.Structs.Compile()
+	if err != nil {
+		return structcollector.Filters{}, fmt.Errorf("compiling structs filter: %w", err)
+	}
+
+	methods, err := p.Methods.Compile()
+	if err != nil {
+		return structcollector.Filters{}, fmt.Errorf("compiling methods filter: %w", err)
+	}
+
+	return structcollector.Filters{
+		Structs: structs,
+		Methods: methods,
+		Kinds:   p.Kinds,
+	}, nil
+}
+
+// processPackage generates the file of a single package. Declared holds the
+// interface names already emitted into the same output package, mapped to the
+// struct they came from, so collisions fail the run instead of producing a
+// file that does not compile.
+func (g Generator) processPackage(pkg *types.Package, p app.Package, out app.Output, declared map[string]string) (outcome, error) {
 	g.reporter.ProcessingPackage(pkg.Path())
 
-	filename := strings.ReplaceAll(g.config.Output.Filename, "{package}", pkg.Name())
+	filters, err := g.filters(p)
+	if err != nil {
+		return skipped, fmt.Errorf("compiling filters: %w", err)
+	}
+
+	// Outside a module the output has no import path, so every internal
+	// package counts as unreachable from it.
+	from, err := pkgload.ImportPath(out.Dir)
+	if err != nil && !errors.Is(err, pkgload.ErrNoModule) {
+		return skipped, fmt.Errorf("resolving output import path: %w", err)
+	}
+
+	output := outputPath(out, pkg)
 
 	fset := token.NewFileSet()
 
-	fset.AddFile(filename, fset.Base(), 1)
+	fset.AddFile(filepath.Base(output), fset.Base(), 1)
 
-	builder := astfile.New(fset, g.config.Output.Package)
+	opts := astfile.Options{
+		Assertions:    out.Emits(app.EmitAssertions),
+		Moq:           out.Emits(app.EmitMoq),
+		Gomock:        out.Emits(app.EmitGomock),
+		Testify:       out.Emits(app.EmitTestify),
+		Delegate:      out.Emits(app.EmitDelegate),
+		Instrument:    out.Emits(app.EmitInstrument),
+		Noop:          out.Emits(app.EmitNoop),
+		Unimplemented: out.Emits(app.EmitUnimplemented),
+		Deep:          g.config.Deep,
+	}
+
+	builder := astfile.New(fset, out.Package, opts)
 
 	is := importset.New()
 	is.Import(pkg)
 
-	structs := g.collector.Collect(pkg)
+	candidates, err := g.embedCandidates(out, pkg)
+	if err != nil {
+		return skipped, fmt.Errorf("resolving embedded interfaces: %w", err)
+	}
+
+	structs, excluded := g.collector.Collect(pkg, filters)
+
+	for _, e := range excluded {
+		g.reporter.SkippedStruct(e.Name, e.Reason)
+	}
 
 	// TODO(calmondev): maybe we can move this counting to the collector?
 	generated := 0
 
+	specs := make([]*astfile.InterfaceSpec, 0, len(structs))
+
 	for _, ss := range structs {
+		name := out.Naming.Name(ss.TypeName.Name(), pkg.Name())
+
+		if p.OnlyUsed {
+			ss = g.narrow(ss, from, name)
+		}
+
+		for _, e := range ss.Excluded {
+			g.reporter.SkippedMethod(ss.TypeName.Name(), e.Name, e.Reason)
+		}
+
 		if !ss.HasMethods() {
-			g.reporter.SkippedStruct(ss.TypeName.Name(), "no methods")
+			reason := "no methods"
+
+			if len(ss.Excluded) > 0 {
+				reason = "all methods excluded"
+			}
+
+			g.reporter.SkippedStruct(ss.TypeName.Name(), reason)
+
+			continue
+		}
 
+		methods, ok, err := g.reachableMethods(ss, from)
+		if err != nil {
+			return skipped, err
+		}
+
+		if !ok {
 			continue
 		}
 
 		typeast.TraverseTypeParams(ss.TypeParams, is.Import)
-		typeast.TraverseFuncs(ss.Methods, is.Import)
+		typeast.TraverseFuncs(methods, is.Import)
 
-		name := ss.TypeName.Name() + g.config.Output.Naming.Suffix
+		source := pkg.Path() + "." + ss.TypeName.Name()
+
+		if other, ok := declared[name]; ok {
+			return skipped, fmt.Errorf("interface name %q of %s collides with the one generated from %s", name, source, other)
+		}
 
-		builder.AddInterface(&astfile.InterfaceSpec{
+		declared[name] = source
+
+		spec := &astfile.InterfaceSpec{
 			Name:       name,
+			Source:     ss.TypeName,
 			TypeParams: ss.TypeParams,
-			Methods:    ss.Methods,
-		})
+			Methods:    methods,
+			Embeds:     embeds(candidates, methods, from),
+		}
 
-		g.reporter.GeneratedInterface(name, ss.TypeName.Name(), len(ss.Methods))
+		for _, e := range spec.Embeds {
+			typeast.Traverse(e, is.Import)
+		}
+
+		if out.Emits(app.EmitAssertions) {
+			spec.Assertion = g.assertion(ss, name, methods)
+		}
+
+		specs = append(specs, spec)
+
+		g.reporter.GeneratedInterface(name, ss.TypeName.Name(), len(methods))
 
 		generated++
 	}
 
+	if p.Facade != "" {
+		spec, err := g.facade(pkg, p, out, from, filters.Methods)
+		if err != nil {
+			return skipped, err
+		}
+
+		if spec != nil {
+			source := pkg.Path() + " functions"
+
+			if other, ok := declared[spec.Name]; ok {
+				return skipped, fmt.Errorf("interface name %q of %s collides with the one generated from %s", spec.Name, source, other)
+			}
+
+			declared[spec.Name] = source
+
+			typeast.TraverseFuncs(spec.Methods, is.Import)
+
+			specs = append(specs, spec)
+
+			g.reporter.GeneratedInterface(spec.Name, p.Facade, len(spec.Methods))
+
+			generated++
+		}
+	}
+
+	if g.config.Compose {
+		compose(specs)
+	}
+
+	for _, spec := range specs {
+		builder.AddInterface(spec)
+	}
+
+	for _, dep := range opts.Packages() {
+		is.Import(dep)
+	}
+
 	for path, alias := range is.Imports() {
 		builder.AddImport(&astfile.ImportSpec{
 			Path:  path,
@@ -105,21 +315,122 @@ func (g Generator) processPackage(pkg *types.Package) error {
 	}
 
 	if !builder.HasInterfaces() {
-		return nil
+		return skipped, nil
 	}
 
 	file, err := builder.Build(is.Qualifier)
 	if err != nil {
-		return fmt.Errorf("building ast file: %w", err)
+		return skipped, fmt.Errorf("building ast file: %w", err)
 	}
 
-	output := filepath.Join(g.config.Output.Dir, filename)
-
-	if err := g.writer.Write(fset, file, output); err != nil {
-		return fmt.Errorf("writing file: %w", err)
+	changed, err := g.writer.Write(fset, file, output)
+	if err != nil {
+		return skipped, fmt.Errorf("writing file: %w", err)
 	}
 
 	g.reporter.PackageCompleted(pkg.Path(), generated)
 
-	return nil
+	if !changed {
+		return unchanged, nil
+	}
+
+	return written, nil
+}
+
+// narrow returns a copy of ss keeping only the methods the scanned packages
+// call, either on the struct itself or on the interface generated from it,
+// so that switching consumers over to the interface keeps it stable.
+func (g Generator) narrow(ss *structcollector.StructSpec, from, name string) *structcollector.StructSpec {
+	narrowed := *ss
+
+	narrowed.Methods = make([]*types.Func, 0, len(ss.Methods))
+	narrowed.Excluded = slices.Clone(ss.Excluded)
+
+	for _, m := range ss.Methods {
+		onStruct := pkgload.MethodUse{Pkg: ss.TypeName.Pkg().Path(), Type: ss.TypeName.Name(), Method: m.Name()}
+		onInterface := pkgload.MethodUse{Pkg: from, Type: name, Method: m.Name()}
+
+		if g.uses[onStruct] || g.uses[onInterface] {
+			narrowed.Methods = append(narrowed.Methods, m)
+
+			continue
+		}
+
+		narrowed.Excluded = append(narrowed.Excluded, &structcollector.Exclusion{
+			Name:   m.Name(),
+			Reason: "not called by any scanned package",
+		})
+	}
+
+	return &narrowed
+}
+
+// reachableMethods applies the unreachable policy to the struct, returning the
+// methods to generate and whether the struct should be generated at all.
+func (g Generator) reachableMethods(ss *structcollector.StructSpec, from string) ([]*types.Func, bool, error) {
+	policy := g.config.UnreachablePolicy()
+	name := ss.TypeName.Name()
+
+	methods, unreachable, err := ss.Reachable(from)
+	if err != nil {
+		if policy == app.UnreachableFail {
+			return nil, false, fmt.Errorf("struct %q: %w", name, err)
+		}
+
+		g.reporter.SkippedStruct(name, err.Error())
+
+		return nil, false, nil
+	}
+
+	for _, u := range unreachable {
+		g.reporter.UnreachableMethod(name, u.Method.Name(), u.Reason.Error())
+	}
+
+	if len(unreachable) > 0 {
+		switch policy {
+		case app.UnreachableFail:
+			u := unreachable[0]
+
+			return nil, false, fmt.Errorf("struct %q method %q: %w", name, u.Method.Name(), u.Reason)
+		case app.UnreachableSkipStruct:
+			g.reporter.SkippedStruct(name, "unreachable types in method signatures")
+
+			return nil, false, nil
+		}
+	}
+
+	if len(methods) == 0 {
+		g.reporter.SkippedStruct(name, "no reachable methods")
+
+		return nil, false, nil
+	}
+
+	return methods, true, nil
+}
+
+// assertion describes the compile-time check of the interface against its
+// source struct, or returns nil when generic type params cannot be satisfied
+// by any concrete type.
+func (g Generator) assertion(ss *structcollector.StructSpec, name string, methods []*types.Func) *astfile.AssertionSpec {
+	args, err := typeast.SampleTypeArgs(ss.TypeParams)
+	if err == nil && len(args) > 0 {
+		_, err = types.Instantiate(nil, ss.TypeName.Type(), args, true)
+	}
+
+	if err != nil {
+		g.reporter.SkippedAssertion(name, err.Error())
+
+		return nil
+	}
+
+	values := types.NewMethodSet(ss.TypeName.Type())
+
+	pointer := slices.ContainsFunc(methods, func(m *types.Func) bool {
+		return values.Lookup(m.Pkg(), m.Name()) == nil
+	})
+
+	return &astfile.AssertionSpec{
+		TypeArgs: args,
+		Pointer:  pointer,
+	}
 }
@@ -0,0 +1,115 @@
+package generator
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/nuvrel/moldable/internal/astfile"
+)
+
+// prune looks for files carrying the moldable header in the output directory
+// of every configured package that were not produced by this run. They are
+// removed when pruning is enabled and only reported otherwise. A directory is
+// only known through the packages writing to it, so the files left behind by
+// a package dropped from the config together with its own directory are not
+// found.
+func (g Generator) prune(produced map[string]bool) error {
+	dirs := make([]string, 0, len(g.config.Packages))
+
+	for _, p := range g.config.Packages {
+		out := g.config.OutputFor(p)
+
+		// filepath.Clean turns an empty directory into the working one,
+		// which the config never asked to write to
+		if out.Dir == "" {
+			continue
+		}
+
+		dir := filepath.Clean(out.Dir)
+
+		if !slices.Contains(dirs, dir) {
+			dirs = append(dirs, dir)
+		}
+	}
+
+	for _, dir := range dirs {
+		orphans, err := orphans(dir, produced)
+		if err != nil {
+			return fmt.Errorf("scanning %q: %w", dir, err)
+		}
+
+		for _, path := range orphans {
+			if !g.config.Prune {
+				g.reporter.OrphanedFile(path)
+
+				continue
+			}
+
+			if err := g.writer.Remove(path); err != nil {
+				return fmt.Errorf("removing %q: %w", path, err)
+			}
+
+			g.reporter.RemovedFile(path)
+		}
+	}
+
+	return nil
+}
+
+func orphans(dir string, produced map[string]bool) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("reading directory: %w", err)
+	}
+
+	paths := make([]string, 0)
+
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasSuffix(e.Name(), ".go") {
+			continue
+		}
+
+		path := filepath.Join(dir, e.Name())
+
+		if produced[path] {
+			continue
+		}
+
+		owned, err := ownedByMoldable(path)
+		if err != nil {
+			return nil, err
+		}
+
+		if owned {
+			paths = append(paths, path)
+		}
+	}
+
+	return paths, nil
+}
+
+func ownedByMoldable(path string) (bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return false, fmt.Errorf("opening %q: %w", path, err)
+	}
+
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+
+	if !scanner.Scan() {
+		return false, scanner.Err()
+	}
+
+	return scanner.Text() == astfile.Header, nil
+}
@@ -0,0 +1,103 @@
+package namefilter
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// Filter decides which names are kept based on include and exclude rules.
+// Rules wrapped in slashes (/^Get.*/) are regular expressions, anything else
+// is a glob pattern where a name without wildcards matches only itself.
+type Filter struct {
+	include []*rule
+	exclude []*rule
+}
+
+type rule struct {
+	pattern string
+	re      *regexp.Regexp
+}
+
+func (r *rule) match(name string) bool {
+	if r.re != nil {
+		return r.re.MatchString(name)
+	}
+
+	ok, _ := path.Match(r.pattern, name)
+
+	return ok
+}
+
+func New(include, exclude []string) (*Filter, error) {
+	inc, err := compile(include)
+	if err != nil {
+		return nil, fmt.Errorf("compiling include rules: %w", err)
+	}
+
+	exc, err := compile(exclude)
+	if err != nil {
+		return nil, fmt.Errorf("compiling exclude rules: %w", err)
+	}
+
+	return &Filter{
+		include: inc,
+		exclude: exc,
+	}, nil
+}
+
+func compile(patterns []string) ([]*rule, error) {
+	rules := make([]*rule, 0, len(patterns))
+
+	for _, p := range patterns {
+		r := &rule{
+			pattern: p,
+		}
+
+		if len(p) > 1 && strings.HasPrefix(p, "/") && strings.HasSuffix(p, "/") {
+			re, err := regexp.Compile(p[1 : len(p)-1])
+			if err != nil {
+				return nil, fmt.Errorf("compiling regexp %q: %w", p, err)
+			}
+
+			r.re = re
+		} else if _, err := path.Match(p, ""); err != nil {
+			return nil, fmt.Errorf("compiling glob %q: %w", p, err)
+		}
+
+		rules = append(rules, r)
+	}
+
+	return rules, nil
+}
+
+// Match reports whether name is kept. When it is not, the returned reason
+// names the rule responsible. A nil filter keeps everything.
+func (f *Filter) Match(name string) (bool, string) {
+	if f == nil {
+		return true, ""
+	}
+
+	if len(f.include) > 0 && !matchAny(f.include, name) {
+		return false, "matches no include rule"
+	}
+
+	for _, r := range f.exclude {
+		if r.match(name) {
+			return false, fmt.Sprintf("matches exclude rule %q", r.pattern)
+		}
+	}
+
+	return true, ""
+}
+
+func matchAny(rules []*rule, name string) bool {
+	for _, r := range rules {
+		if r.match(name) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,72 @@
+package namefilter_test
+
+import (
+	"testing"
+
+	"github.com/nuvrel/moldable/internal/namefilter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+
+	t.Run("invalid include regexp", func(t *testing.T) {
+		t.Parallel()
+
+		f, err := namefilter.New([]string{"/(/"}, nil)
+		assert.ErrorContains(t, err, "compiling include rules")
+
+		assert.Nil(t, f)
+	})
+
+	t.Run("invalid exclude glob", func(t *testing.T) {
+		t.Parallel()
+
+		f, err := namefilter.New(nil, []string{"["})
+		assert.ErrorContains(t, err, "compiling exclude rules")
+
+		assert.Nil(t, f)
+	})
+}
+
+func TestFilterMatch(t *testing.T) {
+	t.Parallel()
+
+	f, err := namefilter.New([]string{"Client", "*Paginator", "/^Get/"}, []string{"Deprecated*", "/Mock$/"})
+	assert.NoError(t, err)
+
+	cases := []struct {
+		name   string
+		want   bool
+		reason string
+	}{
+		{name: "Client", want: true},
+		{name: "ListPaginator", want: true},
+		{name: "GetObject", want: true},
+		{name: "Server", want: false, reason: "matches no include rule"},
+		{name: "DeprecatedPaginator", want: false, reason: `matches exclude rule "Deprecated*"`},
+		{name: "GetMock", want: false, reason: `matches exclude rule "/Mock$/"`},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, reason := f.Match(c.name)
+
+			assert.Equal(t, c.want, got)
+			assert.Equal(t, c.reason, reason)
+		})
+	}
+
+	t.Run("nil filter", func(t *testing.T) {
+		t.Parallel()
+
+		var nf *namefilter.Filter
+
+		got, reason := nf.Match("Anything")
+
+		assert.True(t, got)
+		assert.Empty(t, reason)
+	})
+}
@@ -0,0 +1,45 @@
+package pkgload
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+
+	"golang.org/x/mod/modfile"
+)
+
+// ErrNoModule is returned by ImportPath when no go.mod is found above the
+// directory.
+var ErrNoModule = errors.New("no go.mod found")
+
+// ImportPath resolves the import path a package placed in dir would have by
+// walking up to the nearest go.mod. The directory itself does not need to
+// exist yet.
+func ImportPath(dir string) (string, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", fmt.Errorf("resolving absolute path: %w", err)
+	}
+
+	for current := abs; ; current = filepath.Dir(current) {
+		data, err := os.ReadFile(filepath.Join(current, "go.mod"))
+		if err == nil {
+			rel, err := filepath.Rel(current, abs)
+			if err != nil {
+				return "", fmt.Errorf("resolving path relative to module root: %w", err)
+			}
+
+			return path.Join(modfile.ModulePath(data), filepath.ToSlash(rel)), nil
+		}
+
+		if !errors.Is(err, os.ErrNotExist) {
+			return "", fmt.Errorf("reading go.mod: %w", err)
+		}
+
+		if filepath.Dir(current) == current {
+			return "", fmt.Errorf("%w above %q", ErrNoModule, abs)
+		}
+	}
+}
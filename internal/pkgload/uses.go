@@ -0,0 +1,114 @@
+package pkgload
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// MethodUse identifies a method selected on a value, or in a method
+// expression, of a named type, by the import path and name of that type.
+type MethodUse struct {
+	Pkg    string
+	Type   string
+	Method string
+}
+
+// SkippedPackage is a scanned package whose calls were ignored because it
+// could not be loaded or type-checked.
+type SkippedPackage struct {
+	Path   string
+	Reason string
+}
+
+// Uses loads the packages matching patterns with their syntax and type info
+// and collects every method they select on named types declared in another
+// package. Test files count as callers, as a method only a test calls must
+// stay in the interface for that test to use it. Generated files are ignored,
+// so wrappers emitted for the very types being analyzed do not count as
+// consumers.
+//
+// Packages with errors are skipped and returned rather than failing the scan:
+// a stale generated package that no longer compiles must not prevent the run
+// that would regenerate it. Uses fails only when no package could be scanned.
+func Uses(patterns []string) (map[MethodUse]bool, []SkippedPackage, error) {
+	pkgs, err := packages.Load(
+		&packages.Config{
+			Mode:  packages.NeedName | packages.NeedTypes | packages.NeedSyntax | packages.NeedTypesInfo,
+			Tests: true,
+		},
+		patterns...,
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("loading go packages: %w", err)
+	}
+
+	uses := make(map[MethodUse]bool)
+
+	var skipped []SkippedPackage
+
+	for _, p := range pkgs {
+		if len(p.Errors) > 0 {
+			// the ID tells the test variants apart from the package itself
+			skipped = append(skipped, SkippedPackage{Path: p.ID, Reason: fmt.Sprint(p.Errors)})
+
+			continue
+		}
+
+		for _, file := range p.Syntax {
+			if ast.IsGenerated(file) {
+				continue
+			}
+
+			ast.Inspect(file, func(n ast.Node) bool {
+				expr, ok := n.(*ast.SelectorExpr)
+				if !ok {
+					return true
+				}
+
+				sel, ok := p.TypesInfo.Selections[expr]
+				if !ok || sel.Kind() == types.FieldVal {
+					return true
+				}
+
+				obj := receiverType(sel.Recv())
+				if obj == nil || obj.Pkg() == nil || obj.Pkg().Path() == p.PkgPath {
+					return true
+				}
+
+				uses[MethodUse{
+					Pkg:    obj.Pkg().Path(),
+					Type:   obj.Name(),
+					Method: sel.Obj().Name(),
+				}] = true
+
+				return true
+			})
+		}
+	}
+
+	if len(skipped) == len(pkgs) {
+		return nil, nil, fmt.Errorf("no package matching %q could be scanned", patterns)
+	}
+
+	return uses, skipped, nil
+}
+
+// receiverType returns the declaration of the named type a method was
+// selected on, looking through pointers and instantiations.
+func receiverType(typ types.Type) *types.TypeName {
+	typ = types.Unalias(typ)
+
+	if ptr, ok := typ.(*types.Pointer); ok {
+		typ = types.Unalias(ptr.Elem())
+	}
+
+	named, ok := typ.(*types.Named)
+	if !ok {
+		return nil
+	}
+
+	return named.Origin().Obj()
+}
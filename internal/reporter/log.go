@@ -28,6 +28,34 @@ func (l Log) SkippedStruct(structName, reason string) {
 	l.logger.Info("skipped struct", "name", structName, "reason", reason)
 }
 
+func (l Log) SkippedMethod(structName, methodName, reason string) {
+	l.logger.Info("skipped method", "struct", structName, "method", methodName, "reason", reason)
+}
+
+func (l Log) UnreachableMethod(structName, methodName, reason string) {
+	l.logger.Warn("unreachable method", "struct", structName, "method", methodName, "reason", reason)
+}
+
+func (l Log) SkippedAssertion(interfaceName, reason string) {
+	l.logger.Warn("skipped assertion", "interface", interfaceName, "reason", reason)
+}
+
+func (l Log) SkippedScan(packagePath, reason string) {
+	l.logger.Warn("skipped scanned package, its calls are ignored", "path", packagePath, "reason", reason)
+}
+
 func (l Log) PackageCompleted(packagePath string, interfaceCount int) {
 	l.logger.Info("completed package", "path", packagePath, "interface_count", interfaceCount)
 }
+
+func (l Log) FilesCompleted(written, unchanged int) {
+	l.logger.Info("completed files", "written", written, "unchanged", unchanged)
+}
+
+func (l Log) OrphanedFile(path string) {
+	l.logger.Warn("orphaned generated file, use --prune to remove it", "filepath", path)
+}
+
+func (l Log) RemovedFile(path string) {
+	l.logger.Info("pruned orphaned generated file", "filepath", path)
+}
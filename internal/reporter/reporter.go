@@ -4,5 +4,12 @@ type Reporter interface {
 	ProcessingPackage(packagePath string)
 	GeneratedInterface(interfaceName, structName string, methodCount int)
 	SkippedStruct(structName, reason string)
+	SkippedMethod(structName, methodName, reason string)
+	UnreachableMethod(structName, methodName, reason string)
+	SkippedAssertion(interfaceName, reason string)
+	SkippedScan(packagePath, reason string)
 	PackageCompleted(packagePath string, interfaceCount int)
+	FilesCompleted(written, unchanged int)
+	OrphanedFile(path string)
+	RemovedFile(path string)
 }
@@ -1,17 +1,111 @@
 package structcollector
 
-import "go/types"
+import (
+	"fmt"
+	"go/types"
+	"slices"
 
+	"github.com/nuvrel/moldable/internal/namefilter"
+	"github.com/nuvrel/moldable/internal/typeast"
+)
+
+// Kinds of named types the collector can take methods from, after their
+// underlying type.
+const (
+	KindStruct = "struct"
+	KindBasic  = "basic"
+	KindFunc   = "func"
+	KindMap    = "map"
+	KindSlice  = "slice"
+	KindArray  = "array"
+	KindChan   = "chan"
+)
+
+var Kinds = []string{
+	KindStruct,
+	KindBasic,
+	KindFunc,
+	KindMap,
+	KindSlice,
+	KindArray,
+	KindChan,
+}
+
+// StructSpec describes a named type with methods. Despite the name, it is
+// not necessarily a struct: Kind tells what its underlying type is.
 type StructSpec struct {
 	TypeName   *types.TypeName
 	TypeParams *types.TypeParamList
+	Kind       string
 	Methods    []*types.Func
+	Excluded   []*Exclusion
+}
+
+// Exclusion records a struct or method left out by a filter rule.
+type Exclusion struct {
+	Name   string
+	Reason string
+}
+
+// Filters select the types and methods to keep. Kinds lists the kinds of
+// named types to consider, structs only when empty.
+type Filters struct {
+	Structs *namefilter.Filter
+	Methods *namefilter.Filter
+	Kinds   []string
+}
+
+func (f Filters) includes(kind string) bool {
+	if len(f.Kinds) == 0 {
+		return kind == KindStruct
+	}
+
+	return slices.Contains(f.Kinds, kind)
 }
 
 func (ss StructSpec) HasMethods() bool {
 	return len(ss.Methods) > 0
 }
 
+type UnreachableMethod struct {
+	Method *types.Func
+	Reason error
+}
+
+// Reachable splits the methods of the struct into the ones that can be
+// referenced from the package at the given import path and the ones whose
+// signatures mention types that cannot. An error is returned when the type
+// params of the struct itself are unreachable.
+func (ss StructSpec) Reachable(from string) ([]*types.Func, []*UnreachableMethod, error) {
+	if ss.TypeParams != nil {
+		for i := range ss.TypeParams.Len() {
+			tp := ss.TypeParams.At(i)
+
+			if err := typeast.Reachable(tp.Constraint(), from); err != nil {
+				return nil, nil, fmt.Errorf("type param %q: %w", tp.Obj().Name(), err)
+			}
+		}
+	}
+
+	reachable := make([]*types.Func, 0, len(ss.Methods))
+	unreachable := make([]*UnreachableMethod, 0)
+
+	for _, m := range ss.Methods {
+		if err := typeast.Reachable(m.Type(), from); err != nil {
+			unreachable = append(unreachable, &UnreachableMethod{
+				Method: m,
+				Reason: err,
+			})
+
+			continue
+		}
+
+		reachable = append(reachable, m)
+	}
+
+	return reachable, unreachable, nil
+}
+
 type StructCollector struct {
 	structs map[*types.Package][]*StructSpec
 }
@@ -22,16 +116,62 @@ func New() *StructCollector {
 	}
 }
 
-func (sc *StructCollector) Collect(pkg *types.Package) []*StructSpec {
-	if info, ok := sc.structs[pkg]; ok {
-		return info
+func (sc *StructCollector) Collect(pkg *types.Package, filters Filters) ([]*StructSpec, []*Exclusion) {
+	structs, ok := sc.structs[pkg]
+	if !ok {
+		structs = sc.analyzePackage(pkg)
+
+		sc.structs[pkg] = structs
 	}
 
-	structs := sc.analyzePackage(pkg)
+	return sc.filter(structs, filters)
+}
 
-	sc.structs[pkg] = structs
+func (sc *StructCollector) filter(structs []*StructSpec, filters Filters) ([]*StructSpec, []*Exclusion) {
+	kept := make([]*StructSpec, 0, len(structs))
+	excluded := make([]*Exclusion, 0)
 
-	return structs
+	for _, ss := range structs {
+		name := ss.TypeName.Name()
+
+		if !filters.includes(ss.Kind) {
+			continue
+		}
+
+		if ok, reason := filters.Structs.Match(name); !ok {
+			excluded = append(excluded, &Exclusion{
+				Name:   name,
+				Reason: reason,
+			})
+
+			continue
+		}
+
+		spec := &StructSpec{
+			TypeName:   ss.TypeName,
+			TypeParams: ss.TypeParams,
+			Kind:       ss.Kind,
+			Methods:    make([]*types.Func, 0, len(ss.Methods)),
+			Excluded:   make([]*Exclusion, 0),
+		}
+
+		for _, m := range ss.Methods {
+			if ok, reason := filters.Methods.Match(m.Name()); !ok {
+				spec.Excluded = append(spec.Excluded, &Exclusion{
+					Name:   m.Name(),
+					Reason: reason,
+				})
+
+				continue
+			}
+
+			spec.Methods = append(spec.Methods, m)
+		}
+
+		kept = append(kept, spec)
+	}
+
+	return kept, excluded
 }
 
 func (sc *StructCollector) analyzePackage(pkg *types.Package) []*StructSpec {
@@ -55,21 +195,51 @@ func (sc *StructCollector) analyzeObject(obj types.Object) *StructSpec {
 		return nil
 	}
 
-	_, ok = tn.Type().Underlying().(*types.Struct)
-	if !ok {
+	kind := kindOf(tn.Type().Underlying())
+	if kind == "" {
 		return nil
 	}
 
 	tp := sc.collectTypeParams(tn)
 	methods := sc.collectMethods(tn)
 
+	// other kinds are common without methods, e.g. enums, so only structs
+	// are reported as skipped for lack of them
+	if kind != KindStruct && len(methods) == 0 {
+		return nil
+	}
+
 	return &StructSpec{
 		TypeName:   tn,
 		TypeParams: tp,
+		Kind:       kind,
 		Methods:    methods,
 	}
 }
 
+// kindOf returns the kind of a named type with the given underlying type, or
+// an empty string for the ones that cannot have methods of their own.
+func kindOf(underlying types.Type) string {
+	switch underlying.(type) {
+	case *types.Struct:
+		return KindStruct
+	case *types.Basic:
+		return KindBasic
+	case *types.Signature:
+		return KindFunc
+	case *types.Map:
+		return KindMap
+	case *types.Slice:
+		return KindSlice
+	case *types.Array:
+		return KindArray
+	case *types.Chan:
+		return KindChan
+	default:
+		return ""
+	}
+}
+
 func (sc *StructCollector) collectMethods(tn *types.TypeName) []*types.Func {
 	ms := types.NewMethodSet(types.NewPointer(tn.Type()))
 
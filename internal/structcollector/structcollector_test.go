@@ -0,0 +1,135 @@
+package structcollector_test
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"testing"
+
+	"github.com/nuvrel/moldable/internal/structcollector"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const source = `package p
+
+type Client struct{}
+
+func (*Client) Do() {}
+
+type client struct{}
+
+func (*client) Get() {}
+
+type Alias = client
+
+type Empty struct{}
+
+type Level int
+
+func (Level) String() string { return "" }
+
+type Enum int
+
+type HandlerFunc func()
+
+func (HandlerFunc) Serve() {}
+
+type Header map[string]string
+
+func (Header) Get(string) string { return "" }
+
+type List []int
+
+func (List) Len() int { return 0 }
+
+type Grid [2]int
+
+func (Grid) Len() int { return 0 }
+
+type Pipe chan int
+
+func (Pipe) Close() {}
+
+type Reader interface{ Read() }
+
+type hidden int
+
+func (hidden) String() string { return "" }
+`
+
+func check(t *testing.T) *types.Package {
+	t.Helper()
+
+	fset := token.NewFileSet()
+
+	file, err := parser.ParseFile(fset, "p.go", source, 0)
+	require.NoError(t, err)
+
+	pkg, err := (&types.Config{}).Check("example.com/p", fset, []*ast.File{file}, nil)
+	require.NoError(t, err)
+
+	return pkg
+}
+
+func TestCollectKinds(t *testing.T) {
+	t.Parallel()
+
+	pkg := check(t)
+
+	cases := []struct {
+		name  string
+		kinds []string
+		want  map[string]string
+	}{
+		{
+			name: "structs by default",
+			want: map[string]string{
+				"Alias":  structcollector.KindStruct,
+				"Client": structcollector.KindStruct,
+				"Empty":  structcollector.KindStruct,
+			},
+		},
+		{
+			name:  "selected kinds",
+			kinds: []string{structcollector.KindBasic, structcollector.KindFunc},
+			want: map[string]string{
+				"HandlerFunc": structcollector.KindFunc,
+				"Level":       structcollector.KindBasic,
+			},
+		},
+		{
+			name:  "every kind",
+			kinds: structcollector.Kinds,
+			want: map[string]string{
+				"Alias":       structcollector.KindStruct,
+				"Client":      structcollector.KindStruct,
+				"Empty":       structcollector.KindStruct,
+				"Grid":        structcollector.KindArray,
+				"HandlerFunc": structcollector.KindFunc,
+				"Header":      structcollector.KindMap,
+				"Level":       structcollector.KindBasic,
+				"List":        structcollector.KindSlice,
+				"Pipe":        structcollector.KindChan,
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			structs, excluded := structcollector.New().Collect(pkg, structcollector.Filters{Kinds: c.kinds})
+
+			got := make(map[string]string, len(structs))
+
+			for _, ss := range structs {
+				got[ss.TypeName.Name()] = ss.Kind
+			}
+
+			assert.Equal(t, c.want, got)
+			assert.Empty(t, excluded)
+		})
+	}
+}
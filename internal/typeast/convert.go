@@ -23,7 +23,7 @@ func convert(typ types.Type, qual types.Qualifier) (ast.Expr, error) {
 	case *types.Basic:
 		return convertBasic(t)
 	case *types.Struct:
-		return convertStruct()
+		return convertStruct(t, qual)
 	case *types.Array:
 		return convertArray(t, qual)
 	case *types.Slice:
@@ -35,7 +35,7 @@ func convert(typ types.Type, qual types.Qualifier) (ast.Expr, error) {
 	case *types.Pointer:
 		return convertPointer(t, qual)
 	case *types.Interface:
-		return convertInterface(t)
+		return convertInterface(t, qual)
 	case *types.Signature:
 		return convertSignature(t, qual)
 	case *types.Named:
@@ -55,8 +55,50 @@ func convertBasic(b *types.Basic) (ast.Expr, error) {
 	return ast.NewIdent(b.Name()), nil
 }
 
-func convertStruct() (ast.Expr, error) {
-	return nil, fmt.Errorf("struct is not supported")
+func convertStruct(st *types.Struct, qual types.Qualifier) (ast.Expr, error) {
+	list := make([]*ast.Field, st.NumFields())
+
+	for i := range st.NumFields() {
+		v := st.Field(i)
+
+		typ, err := convert(v.Type(), qual)
+		if err != nil {
+			return nil, fmt.Errorf("converting struct field %d: %w", i, err)
+		}
+
+		field := &ast.Field{
+			Type: typ,
+		}
+
+		if !v.Embedded() {
+			field.Names = []*ast.Ident{ast.NewIdent(v.Name())}
+		}
+
+		if tag := st.Tag(i); tag != "" {
+			field.Tag = convertTag(tag)
+		}
+
+		list[i] = field
+	}
+
+	return &ast.StructType{
+		Fields: &ast.FieldList{
+			List: list,
+		},
+	}, nil
+}
+
+func convertTag(tag string) *ast.BasicLit {
+	value := strconv.Quote(tag)
+
+	if strconv.CanBackquote(tag) {
+		value = "`" + tag + "`"
+	}
+
+	return &ast.BasicLit{
+		Kind:  token.STRING,
+		Value: value,
+	}
 }
 
 func convertArray(arr *types.Array, qual types.Qualifier) (ast.Expr, error) {
@@ -134,12 +176,53 @@ func convertPointer(ptr *types.Pointer, qual types.Qualifier) (ast.Expr, error)
 	}, nil
 }
 
-func convertInterface(iface *types.Interface) (ast.Expr, error) {
-	if !iface.Empty() {
-		return nil, fmt.Errorf("non-empty interface is not supported")
+func convertInterface(iface *types.Interface, qual types.Qualifier) (ast.Expr, error) {
+	if iface.Empty() {
+		return ast.NewIdent("any"), nil
 	}
 
-	return ast.NewIdent("any"), nil
+	// implicit interfaces only appear in constraint position, e.g. [T ~int]
+	if iface.IsImplicit() && iface.NumEmbeddeds() == 1 {
+		expr, err := convert(iface.EmbeddedType(0), qual)
+		if err != nil {
+			return nil, fmt.Errorf("converting implicit interface: %w", err)
+		}
+
+		return expr, nil
+	}
+
+	list := make([]*ast.Field, 0, iface.NumEmbeddeds()+iface.NumExplicitMethods())
+
+	for i := range iface.NumEmbeddeds() {
+		typ, err := convert(iface.EmbeddedType(i), qual)
+		if err != nil {
+			return nil, fmt.Errorf("converting interface embedded type %d: %w", i, err)
+		}
+
+		list = append(list, &ast.Field{
+			Type: typ,
+		})
+	}
+
+	for i := range iface.NumExplicitMethods() {
+		m := iface.ExplicitMethod(i)
+
+		typ, err := convert(m.Type(), qual)
+		if err != nil {
+			return nil, fmt.Errorf("converting interface method %q: %w", m.Name(), err)
+		}
+
+		list = append(list, &ast.Field{
+			Names: []*ast.Ident{ast.NewIdent(m.Name())},
+			Type:  typ,
+		})
+	}
+
+	return &ast.InterfaceType{
+		Methods: &ast.FieldList{
+			List: list,
+		},
+	}, nil
 }
 
 func convertSignature(sig *types.Signature, qual types.Qualifier) (ast.Expr, error) {
@@ -194,11 +277,63 @@ func convertTuple(tup *types.Tuple, qual types.Qualifier) (*ast.FieldList, error
 }
 
 func convertNamed(n *types.Named, qual types.Qualifier) (ast.Expr, error) {
-	return qualifyTypeName(n.Obj(), qual), nil
+	expr := qualifyTypeName(n.Obj(), qual)
+
+	args, err := convertTypeArgs(n.TypeArgs(), qual)
+	if err != nil {
+		return nil, fmt.Errorf("converting named type args: %w", err)
+	}
+
+	return Index(expr, args), nil
 }
 
 func convertAlias(a *types.Alias, qual types.Qualifier) (ast.Expr, error) {
-	return qualifyTypeName(a.Obj(), qual), nil
+	expr := qualifyTypeName(a.Obj(), qual)
+
+	args, err := convertTypeArgs(a.TypeArgs(), qual)
+	if err != nil {
+		return nil, fmt.Errorf("converting alias type args: %w", err)
+	}
+
+	return Index(expr, args), nil
+}
+
+func convertTypeArgs(tl *types.TypeList, qual types.Qualifier) ([]ast.Expr, error) {
+	if tl == nil {
+		return nil, nil
+	}
+
+	args := make([]ast.Expr, tl.Len())
+
+	for i := range tl.Len() {
+		arg, err := convert(tl.At(i), qual)
+		if err != nil {
+			return nil, fmt.Errorf("converting type arg %d: %w", i, err)
+		}
+
+		args[i] = arg
+	}
+
+	return args, nil
+}
+
+// Index applies type arguments to expr, returning expr itself when there are
+// none.
+func Index(expr ast.Expr, args []ast.Expr) ast.Expr {
+	switch len(args) {
+	case 0:
+		return expr
+	case 1:
+		return &ast.IndexExpr{
+			X:     expr,
+			Index: args[0],
+		}
+	default:
+		return &ast.IndexListExpr{
+			X:       expr,
+			Indices: args,
+		}
+	}
 }
 
 func qualifyTypeName(tn *types.TypeName, qual types.Qualifier) ast.Expr {
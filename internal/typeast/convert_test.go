@@ -1,6 +1,7 @@
 package typeast_test
 
 import (
+	"fmt"
 	"go/ast"
 	"go/token"
 	"go/types"
@@ -24,6 +25,31 @@ var (
 	_ types.Type = (*dummy)(nil)
 )
 
+func newGeneric(pkg *types.Package, name string, n int) *types.Named {
+	tps := make([]*types.TypeParam, n)
+
+	for i := range n {
+		tn := types.NewTypeName(token.NoPos, pkg, fmt.Sprintf("T%d", i), nil)
+		tps[i] = types.NewTypeParam(tn, types.NewInterfaceType(nil, nil))
+	}
+
+	named := types.NewNamed(types.NewTypeName(token.NoPos, pkg, name, nil), types.NewStruct(nil, nil), nil)
+	named.SetTypeParams(tps)
+
+	return named
+}
+
+func instantiate(t *testing.T, orig types.Type, args ...types.Type) types.Type {
+	t.Helper()
+
+	inst, err := types.Instantiate(nil, orig, args, false)
+	if err != nil {
+		t.Fatalf("instantiating %s: %v", orig, err)
+	}
+
+	return inst
+}
+
 func TestConvert(t *testing.T) {
 	t.Parallel()
 
@@ -48,6 +74,49 @@ func TestConvert(t *testing.T) {
 			input: types.Typ[types.Int],
 			want:  ast.NewIdent("int"),
 		},
+		{
+			name:  "empty struct",
+			input: types.NewStruct(nil, nil),
+			want: &ast.StructType{
+				Fields: &ast.FieldList{
+					List: []*ast.Field{},
+				},
+			},
+		},
+		{
+			name: "struct",
+			// struct { A int `json:"a"`; p1.; *p2.E "b\x60" }
+			input: types.NewStruct(
+				[]*types.Var{
+					types.NewField(token.NoPos, nil, "A", types.Typ[types.Int], false),
+					types.NewField(token.NoPos, nil, "", n1, true),
+					types.NewField(token.NoPos, nil, "E", types.NewPointer(
+						types.NewNamed(types.NewTypeName(token.NoPos, p2, "E", nil), in, nil),
+					), true),
+				},
+				[]string{`json:"a"`, "", "b`"},
+			),
+			want: &ast.StructType{
+				Fields: &ast.FieldList{
+					List: []*ast.Field{
+						{
+							Names: []*ast.Ident{ast.NewIdent("A")},
+							Type:  ast.NewIdent("int"),
+							Tag:   &ast.BasicLit{Kind: token.STRING, Value: "`json:\"a\"`"},
+						},
+						{
+							Type: &ast.SelectorExpr{X: ast.NewIdent("p1"), Sel: ast.NewIdent("")},
+						},
+						{
+							Type: &ast.StarExpr{
+								X: &ast.SelectorExpr{X: ast.NewIdent("p2"), Sel: ast.NewIdent("E")},
+							},
+							Tag: &ast.BasicLit{Kind: token.STRING, Value: `"b` + "`" + `"`},
+						},
+					},
+				},
+			},
+		},
 		{
 			name:  "array",
 			input: types.NewArray(n1, 10),
@@ -110,6 +179,104 @@ func TestConvert(t *testing.T) {
 			input: in,
 			want:  ast.NewIdent("any"),
 		},
+		{
+			name: "interface with methods and embedded types",
+			// interface { p1.; Read(p []byte) (n int, err error) }
+			input: types.NewInterfaceType(
+				[]*types.Func{
+					types.NewFunc(token.NoPos, nil, "Read", types.NewSignatureType(
+						nil,
+						nil,
+						nil,
+						types.NewTuple(types.NewVar(token.NoPos, nil, "p", types.NewSlice(types.Universe.Lookup("byte").Type()))),
+						types.NewTuple(
+							types.NewVar(token.NoPos, nil, "n", types.Typ[types.Int]),
+							types.NewVar(token.NoPos, nil, "err", types.Universe.Lookup("error").Type()),
+						),
+						false,
+					)),
+				},
+				[]types.Type{n1},
+			),
+			want: &ast.InterfaceType{
+				Methods: &ast.FieldList{
+					List: []*ast.Field{
+						{
+							Type: &ast.SelectorExpr{X: ast.NewIdent("p1"), Sel: ast.NewIdent("")},
+						},
+						{
+							Names: []*ast.Ident{ast.NewIdent("Read")},
+							Type: &ast.FuncType{
+								Params: &ast.FieldList{
+									List: []*ast.Field{
+										{
+											Names: []*ast.Ident{ast.NewIdent("p")},
+											Type:  &ast.ArrayType{Elt: ast.NewIdent("byte")},
+										},
+									},
+								},
+								Results: &ast.FieldList{
+									List: []*ast.Field{
+										{
+											Names: []*ast.Ident{ast.NewIdent("n")},
+											Type:  ast.NewIdent("int"),
+										},
+										{
+											Names: []*ast.Ident{ast.NewIdent("err")},
+											Type:  ast.NewIdent("error"),
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "interface with type set",
+			// interface { ~int | string; comparable }
+			input: types.NewInterfaceType(nil, []types.Type{
+				types.NewUnion([]*types.Term{
+					types.NewTerm(true, types.Typ[types.Int]),
+					types.NewTerm(false, types.Typ[types.String]),
+				}),
+				types.Universe.Lookup("comparable").Type(),
+			}),
+			want: &ast.InterfaceType{
+				Methods: &ast.FieldList{
+					List: []*ast.Field{
+						{
+							Type: &ast.BinaryExpr{
+								X:  &ast.UnaryExpr{Op: token.TILDE, X: ast.NewIdent("int")},
+								Op: token.OR,
+								Y:  ast.NewIdent("string"),
+							},
+						},
+						{
+							Type: ast.NewIdent("comparable"),
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "implicit interface",
+			// [T ~int]
+			input: func() types.Type {
+				iface := types.NewInterfaceType(nil, []types.Type{
+					types.NewUnion([]*types.Term{types.NewTerm(true, types.Typ[types.Int])}),
+				})
+
+				iface.MarkImplicit()
+
+				return iface
+			}(),
+			want: &ast.UnaryExpr{
+				Op: token.TILDE,
+				X:  ast.NewIdent("int"),
+			},
+		},
 		{
 			name: "signature with params",
 			input: func() *types.Signature {
@@ -194,6 +361,54 @@ func TestConvert(t *testing.T) {
 			input: types.NewNamed(types.NewTypeName(token.NoPos, nil, "", nil), in, nil),
 			want:  &ast.Ident{Name: ""},
 		},
+		{
+			name: "instantiated named",
+			// p1.G[p2.]
+			input: func() types.Type {
+				return instantiate(t, newGeneric(p1, "G", 1), n2)
+			}(),
+			want: &ast.IndexExpr{
+				X:     &ast.SelectorExpr{X: ast.NewIdent("p1"), Sel: ast.NewIdent("G")},
+				Index: &ast.SelectorExpr{X: ast.NewIdent("p2"), Sel: ast.NewIdent("")},
+			},
+		},
+		{
+			name: "instantiated named with nested type args",
+			// p1.G[p2.], p1.G[p1.G[p2.], int]
+			input: func() types.Type {
+				g1 := newGeneric(p1, "G", 1)
+				g2 := newGeneric(p1, "G", 2)
+
+				return instantiate(t, g2, instantiate(t, g1, n2), types.Typ[types.Int])
+			}(),
+			want: &ast.IndexListExpr{
+				X: &ast.SelectorExpr{X: ast.NewIdent("p1"), Sel: ast.NewIdent("G")},
+				Indices: []ast.Expr{
+					&ast.IndexExpr{
+						X:     &ast.SelectorExpr{X: ast.NewIdent("p1"), Sel: ast.NewIdent("G")},
+						Index: &ast.SelectorExpr{X: ast.NewIdent("p2"), Sel: ast.NewIdent("")},
+					},
+					ast.NewIdent("int"),
+				},
+			},
+		},
+		{
+			name: "instantiated alias",
+			// p1.A[p2.]
+			input: func() types.Type {
+				tn := types.NewTypeName(token.NoPos, p1, "A", nil)
+				tp := types.NewTypeParam(types.NewTypeName(token.NoPos, p1, "T", nil), in)
+				a := types.NewAlias(tn, types.NewSlice(tp))
+
+				a.SetTypeParams([]*types.TypeParam{tp})
+
+				return instantiate(t, a, n2)
+			}(),
+			want: &ast.IndexExpr{
+				X:     &ast.SelectorExpr{X: ast.NewIdent("p1"), Sel: ast.NewIdent("A")},
+				Index: &ast.SelectorExpr{X: ast.NewIdent("p2"), Sel: ast.NewIdent("")},
+			},
+		},
 		{
 			name:  "alias",
 			input: types.NewAlias(tn1, n1),
@@ -257,9 +472,11 @@ func TestConvert(t *testing.T) {
 			err:   "unsupported type",
 		},
 		{
-			name:  "struct",
-			input: types.NewStruct(nil, nil),
-			err:   "struct is not supported",
+			name: "struct with unsupported field type",
+			input: types.NewStruct([]*types.Var{
+				types.NewField(token.NoPos, nil, "A", &dummy{}, false),
+			}, nil),
+			err: "converting struct field 0",
 		},
 		{
 			name:  "array with unsupported type",
@@ -292,9 +509,36 @@ func TestConvert(t *testing.T) {
 			err:   "converting pointer elem",
 		},
 		{
-			name:  "non-empty interface",
+			name:  "interface with unsupported embedded type",
 			input: types.NewInterfaceType(nil, []types.Type{&dummy{}}),
-			err:   "non-empty interface is not supported",
+			err:   "converting interface embedded type 0",
+		},
+		{
+			name: "interface with unsupported method type",
+			input: types.NewInterfaceType([]*types.Func{
+				types.NewFunc(token.NoPos, nil, "M", types.NewSignatureType(
+					nil,
+					nil,
+					nil,
+					types.NewTuple(types.NewVar(token.NoPos, nil, "", &dummy{})),
+					nil,
+					false,
+				)),
+			}, nil),
+			err: `converting interface method "M"`,
+		},
+		{
+			name: "implicit interface with unsupported type",
+			input: func() types.Type {
+				iface := types.NewInterfaceType(nil, []types.Type{
+					types.NewUnion([]*types.Term{types.NewTerm(false, &dummy{})}),
+				})
+
+				iface.MarkImplicit()
+
+				return iface
+			}(),
+			err: "converting implicit interface",
 		},
 		{
 			name: "signature with unsupported type in type params",
@@ -324,6 +568,13 @@ func TestConvert(t *testing.T) {
 			}(),
 			err: "converting signature results",
 		},
+		{
+			name: "named with unsupported type arg",
+			input: func() types.Type {
+				return instantiate(t, newGeneric(p1, "G", 1), types.NewSlice(&dummy{}))
+			}(),
+			err: "converting named type args",
+		},
 		{
 			name: "union with unsupported [0] term type",
 			input: func() types.Type {
@@ -0,0 +1,146 @@
+package typeast
+
+import (
+	"fmt"
+	"go/types"
+	"strings"
+)
+
+// Reachable reports an error naming the first part of typ that cannot be
+// referenced from the package with the given import path, either because it
+// is unexported or because it lives in an internal package that path is not
+// allowed to import.
+func Reachable(typ types.Type, from string) error {
+	if typ == nil {
+		return nil
+	}
+
+	switch t := typ.(type) {
+	case *types.Struct:
+		for i := range t.NumFields() {
+			f := t.Field(i)
+
+			if err := reachableObject(f, from); err != nil {
+				return fmt.Errorf("field %q: %w", f.Name(), err)
+			}
+
+			if err := Reachable(f.Type(), from); err != nil {
+				return err
+			}
+		}
+	case *types.Array:
+		return Reachable(t.Elem(), from)
+	case *types.Slice:
+		return Reachable(t.Elem(), from)
+	case *types.Map:
+		if err := Reachable(t.Key(), from); err != nil {
+			return err
+		}
+
+		return Reachable(t.Elem(), from)
+	case *types.Chan:
+		return Reachable(t.Elem(), from)
+	case *types.Pointer:
+		return Reachable(t.Elem(), from)
+	case *types.Interface:
+		for i := range t.NumEmbeddeds() {
+			if err := Reachable(t.EmbeddedType(i), from); err != nil {
+				return err
+			}
+		}
+
+		for i := range t.NumExplicitMethods() {
+			m := t.ExplicitMethod(i)
+
+			if err := reachableObject(m, from); err != nil {
+				return fmt.Errorf("method %q: %w", m.Name(), err)
+			}
+
+			if err := Reachable(m.Type(), from); err != nil {
+				return err
+			}
+		}
+	case *types.Union:
+		for i := range t.Len() {
+			if err := Reachable(t.Term(i).Type(), from); err != nil {
+				return err
+			}
+		}
+	case *types.Signature:
+		for _, tup := range []*types.Tuple{t.Params(), t.Results()} {
+			if tup == nil {
+				continue
+			}
+
+			for i := range tup.Len() {
+				if err := Reachable(tup.At(i).Type(), from); err != nil {
+					return err
+				}
+			}
+		}
+	case *types.Named:
+		if err := reachableObject(t.Obj(), from); err != nil {
+			return err
+		}
+
+		return reachableTypeArgs(t.TypeArgs(), from)
+	case *types.Alias:
+		if err := reachableObject(t.Obj(), from); err != nil {
+			return err
+		}
+
+		return reachableTypeArgs(t.TypeArgs(), from)
+	}
+
+	return nil
+}
+
+func reachableTypeArgs(tl *types.TypeList, from string) error {
+	if tl == nil {
+		return nil
+	}
+
+	for i := range tl.Len() {
+		if err := Reachable(tl.At(i), from); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func reachableObject(obj types.Object, from string) error {
+	pkg := obj.Pkg()
+	if pkg == nil || pkg.Path() == from {
+		return nil
+	}
+
+	if !obj.Exported() {
+		return fmt.Errorf("%s.%s is unexported", pkg.Name(), obj.Name())
+	}
+
+	if !Importable(pkg.Path(), from) {
+		return fmt.Errorf("package %q is internal", pkg.Path())
+	}
+
+	return nil
+}
+
+// Importable reports whether the package at path may be imported from the
+// package at from, following the internal directory rule of the go command.
+func Importable(path, from string) bool {
+	var parent string
+
+	switch {
+	case strings.HasSuffix(path, "/internal"):
+		parent = strings.TrimSuffix(path, "/internal")
+	case strings.Contains(path, "/internal/"):
+		parent = path[:strings.LastIndex(path, "/internal/")]
+	case path == "internal" || strings.HasPrefix(path, "internal/"):
+		return false
+	default:
+		return true
+	}
+
+	return from == parent || strings.HasPrefix(from, parent+"/")
+}
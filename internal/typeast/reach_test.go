@@ -0,0 +1,125 @@
+package typeast_test
+
+import (
+	"go/token"
+	"go/types"
+	"testing"
+
+	"github.com/nuvrel/moldable/internal/typeast"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReachable(t *testing.T) {
+	t.Parallel()
+
+	p1 := types.NewPackage("example.com/p1", "p1")
+	in := types.NewPackage("example.com/p1/internal/in", "in")
+
+	in2 := types.NewInterfaceType(nil, nil)
+
+	exported := types.NewNamed(types.NewTypeName(token.NoPos, p1, "Exported", nil), in2, nil)
+	unexported := types.NewNamed(types.NewTypeName(token.NoPos, p1, "unexported", nil), in2, nil)
+	internal := types.NewNamed(types.NewTypeName(token.NoPos, in, "Internal", nil), in2, nil)
+
+	cases := []struct {
+		name  string
+		input types.Type
+		from  string
+		err   string
+	}{
+		{
+			name:  "nil",
+			input: nil,
+			from:  "example.com/out",
+		},
+		{
+			name:  "exported",
+			input: types.NewMap(types.Typ[types.String], types.NewSlice(exported)),
+			from:  "example.com/out",
+		},
+		{
+			name:  "unexported",
+			input: types.NewPointer(unexported),
+			from:  "example.com/out",
+			err:   "p1.unexported is unexported",
+		},
+		{
+			name:  "unexported from same package",
+			input: unexported,
+			from:  "example.com/p1",
+		},
+		{
+			name:  "internal",
+			input: types.NewChan(types.SendRecv, internal),
+			from:  "example.com/out",
+			err:   `package "example.com/p1/internal/in" is internal`,
+		},
+		{
+			name:  "internal from parent",
+			input: internal,
+			from:  "example.com/p1/contract",
+		},
+		{
+			name: "struct with unexported field",
+			input: types.NewStruct([]*types.Var{
+				types.NewField(token.NoPos, p1, "x", types.Typ[types.Int], false),
+			}, nil),
+			from: "example.com/out",
+			err:  `field "x": p1.x is unexported`,
+		},
+		{
+			name: "signature with unexported result",
+			input: types.NewSignatureType(
+				nil,
+				nil,
+				nil,
+				types.NewTuple(types.NewVar(token.NoPos, nil, "", exported)),
+				types.NewTuple(types.NewVar(token.NoPos, nil, "", types.NewArray(unexported, 1))),
+				false,
+			),
+			from: "example.com/out",
+			err:  "p1.unexported is unexported",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := typeast.Reachable(c.input, c.from)
+
+			if c.err == "" {
+				assert.NoError(t, err)
+
+				return
+			}
+
+			assert.ErrorContains(t, err, c.err)
+		})
+	}
+}
+
+func TestImportable(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		path string
+		from string
+		want bool
+	}{
+		{path: "example.com/a", from: "example.com/b", want: true},
+		{path: "example.com/a/internal", from: "example.com/a", want: true},
+		{path: "example.com/a/internal/b", from: "example.com/a/c/d", want: true},
+		{path: "example.com/a/internal/b", from: "example.com/ab", want: false},
+		{path: "example.com/a/internal/b", from: "example.com/b", want: false},
+		{path: "internal/poll", from: "example.com/b", want: false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.path+" from "+c.from, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, c.want, typeast.Importable(c.path, c.from))
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package typeast
+
+import (
+	"fmt"
+	"go/types"
+)
+
+// SampleTypeArgs picks, for every type param in tpl, a concrete type that
+// satisfies its constraint, so generic types can be instantiated where no type
+// params are in scope. The first term of a type set is used, and any for
+// constraints without one.
+func SampleTypeArgs(tpl *types.TypeParamList) ([]types.Type, error) {
+	if tpl == nil {
+		return nil, nil
+	}
+
+	args := make([]types.Type, tpl.Len())
+
+	for i := range tpl.Len() {
+		tp := tpl.At(i)
+
+		arg, err := sampleTypeArg(tp)
+		if err != nil {
+			return nil, fmt.Errorf("sampling type param %q: %w", tp.Obj().Name(), err)
+		}
+
+		args[i] = arg
+	}
+
+	return args, nil
+}
+
+func sampleTypeArg(tp *types.TypeParam) (types.Type, error) {
+	iface, ok := tp.Constraint().Underlying().(*types.Interface)
+	if !ok {
+		return nil, fmt.Errorf("unexpected constraint %s", tp.Constraint())
+	}
+
+	candidates := make([]types.Type, 0)
+
+	for i := range iface.NumEmbeddeds() {
+		embedded := iface.EmbeddedType(i)
+
+		if u, ok := embedded.(*types.Union); ok {
+			for j := range u.Len() {
+				candidates = append(candidates, u.Term(j).Type())
+			}
+
+			continue
+		}
+
+		if _, ok := embedded.Underlying().(*types.Interface); !ok {
+			candidates = append(candidates, embedded)
+		}
+	}
+
+	if len(candidates) == 0 {
+		candidates = append(candidates, types.Universe.Lookup("any").Type())
+	}
+
+	for _, c := range candidates {
+		if !hasTypeParam(c) && types.Satisfies(c, iface) {
+			return c, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no type satisfies constraint %s", tp.Constraint())
+}
+
+func hasTypeParam(typ types.Type) bool {
+	switch t := typ.(type) {
+	case *types.TypeParam:
+		return true
+	case *types.Array:
+		return hasTypeParam(t.Elem())
+	case *types.Slice:
+		return hasTypeParam(t.Elem())
+	case *types.Map:
+		return hasTypeParam(t.Key()) || hasTypeParam(t.Elem())
+	case *types.Chan:
+		return hasTypeParam(t.Elem())
+	case *types.Pointer:
+		return hasTypeParam(t.Elem())
+	case *types.Signature:
+		for _, tup := range []*types.Tuple{t.Params(), t.Results()} {
+			for i := range tup.Len() {
+				if hasTypeParam(tup.At(i).Type()) {
+					return true
+				}
+			}
+		}
+	case *types.Named:
+		for i := range t.TypeArgs().Len() {
+			if hasTypeParam(t.TypeArgs().At(i)) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,91 @@
+package typeast_test
+
+import (
+	"go/token"
+	"go/types"
+	"testing"
+
+	"github.com/nuvrel/moldable/internal/typeast"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSampleTypeArgs(t *testing.T) {
+	t.Parallel()
+
+	p1 := types.NewPackage("p1", "p1")
+
+	anyType := types.Universe.Lookup("any").Type()
+	comparable := types.Universe.Lookup("comparable").Type()
+
+	newTypeParams := func(constraints ...types.Type) *types.TypeParamList {
+		tps := make([]*types.TypeParam, len(constraints))
+
+		for i, c := range constraints {
+			tps[i] = types.NewTypeParam(types.NewTypeName(token.NoPos, p1, "T", nil), c)
+		}
+
+		named := types.NewNamed(types.NewTypeName(token.NoPos, p1, "S", nil), types.NewStruct(nil, nil), nil)
+		named.SetTypeParams(tps)
+
+		return named.TypeParams()
+	}
+
+	union := func(terms ...*types.Term) types.Type {
+		return types.NewInterfaceType(nil, []types.Type{types.NewUnion(terms)})
+	}
+
+	successes := []struct {
+		name  string
+		input *types.TypeParamList
+		want  []types.Type
+	}{
+		{
+			name:  "nil",
+			input: nil,
+			want:  nil,
+		},
+		{
+			name:  "any and comparable",
+			input: newTypeParams(anyType, comparable),
+			want:  []types.Type{anyType, anyType},
+		},
+		{
+			name: "union",
+			input: newTypeParams(union(
+				types.NewTerm(true, types.Typ[types.Int64]),
+				types.NewTerm(false, types.Typ[types.String]),
+			)),
+			want: []types.Type{types.Typ[types.Int64]},
+		},
+	}
+
+	for _, s := range successes {
+		t.Run(s.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := typeast.SampleTypeArgs(s.input)
+
+			assert.NoError(t, err)
+			assert.Equal(t, s.want, got)
+		})
+	}
+
+	t.Run("unsatisfiable", func(t *testing.T) {
+		t.Parallel()
+
+		stringer := types.NewInterfaceType([]*types.Func{
+			types.NewFunc(token.NoPos, p1, "String", types.NewSignatureType(
+				nil,
+				nil,
+				nil,
+				nil,
+				types.NewTuple(types.NewVar(token.NoPos, nil, "", types.Typ[types.String])),
+				false,
+			)),
+		}, []types.Type{types.NewUnion([]*types.Term{types.NewTerm(true, types.Typ[types.Int])})})
+
+		_, err := typeast.SampleTypeArgs(newTypeParams(stringer))
+
+		assert.ErrorContains(t, err, `sampling type param "T": no type satisfies constraint`)
+	})
+}
@@ -10,6 +10,10 @@ func Traverse(typ types.Type, fn Func) {
 	}
 
 	switch t := typ.(type) {
+	case *types.Struct:
+		for i := range t.NumFields() {
+			Traverse(t.Field(i).Type(), fn)
+		}
 	case *types.Array:
 		Traverse(t.Elem(), fn)
 	case *types.Slice:
@@ -25,6 +29,14 @@ func Traverse(typ types.Type, fn Func) {
 		for i := range t.NumEmbeddeds() {
 			Traverse(t.EmbeddedType(i), fn)
 		}
+
+		for i := range t.NumExplicitMethods() {
+			Traverse(t.ExplicitMethod(i).Type(), fn)
+		}
+	case *types.Union:
+		for i := range t.Len() {
+			Traverse(t.Term(i).Type(), fn)
+		}
 	case *types.Signature:
 		if params := t.Params(); params != nil {
 			for i := range params.Len() {
@@ -41,15 +53,29 @@ func Traverse(typ types.Type, fn Func) {
 		if pkg := t.Obj().Pkg(); pkg != nil {
 			fn(pkg)
 		}
+
+		traverseTypeArgs(t.TypeArgs(), fn)
 	case *types.Alias:
 		if pkg := t.Obj().Pkg(); pkg != nil {
 			fn(pkg)
 		}
+
+		traverseTypeArgs(t.TypeArgs(), fn)
 	case *types.TypeParam:
 		Traverse(t.Constraint(), fn)
 	}
 }
 
+func traverseTypeArgs(tl *types.TypeList, fn Func) {
+	if tl == nil {
+		return
+	}
+
+	for i := range tl.Len() {
+		Traverse(tl.At(i), fn)
+	}
+}
+
 func TraverseTypeParams(tpl *types.TypeParamList, fn Func) {
 	if tpl == nil {
 		return
@@ -30,6 +30,15 @@ func TestTraverse(t *testing.T) {
 			input: nil,
 			want:  nil,
 		},
+		{
+			name: "struct",
+			// struct { A p1.; p2. }
+			input: types.NewStruct([]*types.Var{
+				types.NewField(token.NoPos, nil, "A", n1, false),
+				types.NewField(token.NoPos, nil, "", n2, true),
+			}, nil),
+			want: []string{"p1", "p2"},
+		},
 		{
 			name: "array",
 			// [10]p1.
@@ -66,6 +75,30 @@ func TestTraverse(t *testing.T) {
 			input: types.NewInterfaceType(nil, []types.Type{n1}),
 			want:  []string{"p1"},
 		},
+		{
+			name: "interface with methods",
+			// interface { M(p1.) p2. }
+			input: types.NewInterfaceType([]*types.Func{
+				types.NewFunc(token.NoPos, nil, "M", types.NewSignatureType(
+					nil,
+					nil,
+					nil,
+					types.NewTuple(types.NewVar(token.NoPos, nil, "", n1)),
+					types.NewTuple(types.NewVar(token.NoPos, nil, "", n2)),
+					false,
+				)),
+			}, nil),
+			want: []string{"p1", "p2"},
+		},
+		{
+			name: "union",
+			// p1. | ~p2.
+			input: types.NewUnion([]*types.Term{
+				types.NewTerm(false, n1),
+				types.NewTerm(true, n2),
+			}),
+			want: []string{"p1", "p2"},
+		},
 		{
 			name: "signature",
 			// func (p1.) (p2.)
@@ -98,6 +131,16 @@ func TestTraverse(t *testing.T) {
 			input: types.NewAlias(tn1, n1),
 			want:  []string{"p1"},
 		},
+		{
+			name: "instantiated named",
+			// p1.G[p1.G[p2.]]
+			input: func() types.Type {
+				g := newGeneric(tn1.Pkg(), "G", 1)
+
+				return instantiate(t, g, instantiate(t, g, n2))
+			}(),
+			want: []string{"p1", "p1", "p2"},
+		},
 		{
 			name: "type param",
 			// [p1. p2.]